@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// auditCheck is a catalog hygiene rule
+type auditCheck struct {
+	name  string
+	title string
+}
+
+// Catalog audit checks, in report order
+var auditChecks = []auditCheck{
+	{"missing_owner", "Missing Owner"},
+	{"missing_oncall", "Missing On-Call"},
+	{"missing_slack_channel", "Empty Slack Channel"},
+	{"unknown_tier", "Unknown Criticality Tier"},
+	{"unknown_release_state", "Unknown Release State"},
+	{"no_repositories", "No Repositories"},
+	{"repository_sync_error", "Repositories with GitHub Sync Errors"},
+	{"archived_repository", "Archived Repositories Still Linked"},
+	{"deleted_provider", "Dependencies on Deleted Projects"},
+}
+
+// AuditFinding is one hygiene problem of a project
+type AuditFinding struct {
+	Project Project
+	// Detail names the repository or dependency at fault, if any
+	Detail string
+}
+
+// CatalogAudit is the result of a catalog data-quality audit
+type CatalogAudit struct {
+	Team     string
+	Category string
+	Audited  int
+	// Findings maps a check name to the problems it found
+	Findings map[string][]AuditFinding
+}
+
+// GetCatalogAudit loads the whole catalog and reports hygiene problems of the projects
+// owned by or on call with team and in category; empty filters match every project
+func (s *ProjectService) GetCatalogAudit(ctx context.Context, team, category string) (*CatalogAuditResult, error) {
+	catalog, err := s.source.Catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := auditCatalog(catalog, team, category)
+	return &CatalogAuditResult{
+		Audit:         audit,
+		FormattedText: formatCatalogAudit(audit),
+	}, nil
+}
+
+// auditCatalog runs every audit check against the projects matching the filters. Findings
+// are ordered by permalink.
+func auditCatalog(catalog *Snapshot, team, category string) *CatalogAudit {
+	audit := &CatalogAudit{Team: team, Category: category, Findings: make(map[string][]AuditFinding)}
+
+	projects := make(map[int]Project)
+	for _, project := range catalog.Projects {
+		projects[project.ID] = project
+	}
+	repositories := make(map[int]Repository)
+	for _, repo := range catalog.Repositories {
+		repositories[repo.ID] = repo
+	}
+	dependencies := make(map[int][]ProjectDependency)
+	for _, dep := range catalog.ProjectDependencies {
+		if dep.DeletedAt == nil {
+			dependencies[dep.DependentProjectID] = append(dependencies[dep.DependentProjectID], dep)
+		}
+	}
+
+	for _, deps := range dependencies {
+		sort.Slice(deps, func(i, j int) bool { return deps[i].ProvidingProjectID < deps[j].ProvidingProjectID })
+	}
+
+	add := func(check string, project Project, detail string) {
+		audit.Findings[check] = append(audit.Findings[check], AuditFinding{Project: project, Detail: detail})
+	}
+
+	ordered := append([]Project(nil), catalog.Projects...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Permalink < ordered[j].Permalink })
+
+	for _, project := range ordered {
+		if project.DeletedAt != nil {
+			continue
+		}
+		if team != "" && !strings.EqualFold(project.ProjectStakeholderOwner, team) && !strings.EqualFold(project.ProjectStakeholderOncall, team) {
+			continue
+		}
+		if category != "" && !strings.EqualFold(project.Category, category) {
+			continue
+		}
+		audit.Audited++
+
+		if strings.TrimSpace(project.ProjectStakeholderOwner) == "" {
+			add("missing_owner", project, "")
+		}
+		if strings.TrimSpace(project.ProjectStakeholderOncall) == "" {
+			add("missing_oncall", project, "")
+		}
+		if strings.TrimSpace(project.SlackChannel) == "" {
+			add("missing_slack_channel", project, "")
+		}
+		if !project.CriticalityTier.Known() {
+			add("unknown_tier", project, "")
+		}
+		if !project.ReleaseState.Known() {
+			add("unknown_release_state", project, "")
+		}
+		if len(project.ProjectRepositoryURLs) == 0 && len(project.RepositoriesIDs) == 0 {
+			add("no_repositories", project, "")
+		}
+
+		for _, id := range project.RepositoriesIDs {
+			repo, ok := repositories[id]
+			if !ok || repo.DeletedAt != nil {
+				continue
+			}
+			if repo.GithubSyncError {
+				add("repository_sync_error", project, repositoryLabel(repo))
+			}
+			if repo.Archived {
+				add("archived_repository", project, repositoryLabel(repo))
+			}
+		}
+
+		for _, dep := range dependencies[project.ID] {
+			provider, ok := projects[dep.ProvidingProjectID]
+			switch {
+			case !ok:
+				add("deleted_provider", project, fmt.Sprintf("project ID %d (not in the catalog)", dep.ProvidingProjectID))
+			case provider.DeletedAt != nil:
+				add("deleted_provider", project, fmt.Sprintf("`%s` (deleted %s)", provider.Permalink, *provider.DeletedAt))
+			}
+		}
+	}
+
+	return audit
+}
+
+// repositoryLabel returns the name and URL of a repository
+func repositoryLabel(repo Repository) string {
+	if repo.URL == "" {
+		return repo.Name
+	}
+	return fmt.Sprintf("%s (%s)", repo.Name, repo.URL)
+}
+
+// formatCatalogAudit formats a catalog audit report for display
+func formatCatalogAudit(audit *CatalogAudit) string {
+	result := "# Catalog Audit\n\n"
+
+	var scope []string
+	if audit.Team != "" {
+		scope = append(scope, "team "+audit.Team)
+	}
+	if audit.Category != "" {
+		scope = append(scope, "category "+audit.Category)
+	}
+	if len(scope) == 0 {
+		scope = append(scope, "all projects")
+	}
+
+	affected := make(map[int]bool)
+	for _, findings := range audit.Findings {
+		for _, finding := range findings {
+			affected[finding.Project.ID] = true
+		}
+	}
+	result += fmt.Sprintf("**Scope:** %s\n\n", strings.Join(scope, ", "))
+	result += fmt.Sprintf("Audited %d projects; problems found in %d.\n", audit.Audited, len(affected))
+
+	result += "\n## Summary\n"
+	for _, check := range auditChecks {
+		result += fmt.Sprintf("- **%s:** %d\n", check.title, len(audit.Findings[check.name]))
+	}
+
+	for _, check := range auditChecks {
+		findings := audit.Findings[check.name]
+		if len(findings) == 0 {
+			continue
+		}
+		result += fmt.Sprintf("\n## %s (%d)\n", check.title, len(findings))
+		for _, finding := range findings {
+			result += fmt.Sprintf("- `%s` (%s, owner: %s)", finding.Project.Permalink, finding.Project.Name, valueOrNone(finding.Project.ProjectStakeholderOwner))
+			if finding.Detail != "" {
+				result += ": " + finding.Detail
+			}
+			result += "\n"
+		}
+	}
+
+	return result
+}
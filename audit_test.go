@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// auditSnapshot returns a catalog with one clean project and one with every problem
+func auditSnapshot() *Snapshot {
+	clean := testProject(1, "checkout", "Team Checkout", "Tier 1")
+	clean.ProjectStakeholderOncall = "Checkout On-Call"
+	clean.SlackChannel = "checkout"
+	clean.ReleaseState = "GA"
+	clean.RepositoriesIDs = []int{100}
+
+	messy := testProject(2, "legacy-reports", "", "Unknown")
+	messy.ReleaseState = "Unknown"
+	messy.RepositoriesIDs = []int{101, 102}
+
+	repoless := testProject(4, "scratch", "Team Checkout", "Tier 3")
+	repoless.ProjectStakeholderOncall = "Checkout On-Call"
+	repoless.SlackChannel = "scratch"
+	repoless.ReleaseState = "Beta"
+
+	retired := testProject(3, "old-billing", "Team Payments", "Tier 2")
+	retired.DeletedAt = deletedAt()
+
+	return &Snapshot{
+		Projects: []Project{clean, messy, retired, repoless},
+		ProjectDependencies: []ProjectDependency{
+			testDependency(1, 1, 2),
+			testDependency(2, 2, 3),
+			testDependency(3, 2, 99),
+		},
+		Repositories: []Repository{
+			{ID: 100, Name: "checkout", URL: "https://github.com/example/checkout"},
+			{ID: 101, Name: "reports", URL: "https://github.com/example/reports", Archived: true},
+			{ID: 102, Name: "reports-etl", URL: "https://github.com/example/reports-etl", GithubSyncError: true},
+		},
+	}
+}
+
+func TestGetCatalogAudit(t *testing.T) {
+	service := NewProjectService(NewSnapshotSource(auditSnapshot()), NewValidator())
+
+	result, err := service.GetCatalogAudit(context.Background(), "", "")
+	if err != nil {
+		t.Fatalf("GetCatalogAudit failed: %v", err)
+	}
+	audit := result.Audit
+	if audit.Audited != 3 {
+		t.Errorf("audited %d projects, want 3 without the deleted one", audit.Audited)
+	}
+	for _, check := range auditChecks {
+		want := 1
+		if check.name == "deleted_provider" {
+			want = 2
+		}
+		if got := len(audit.Findings[check.name]); got != want {
+			t.Errorf("%s findings = %d, want %d", check.name, got, want)
+		}
+	}
+	assertGolden(t, "catalog_audit", result.FormattedText)
+
+	filtered, err := service.GetCatalogAudit(context.Background(), "team checkout", "service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filtered.Audit.Audited != 2 || len(filtered.Audit.Findings) != 1 {
+		t.Errorf("expected only the repository problem of team checkout, got %+v", filtered.Audit.Findings)
+	}
+}
+
+func TestCatalogAuditExamplePayloads(t *testing.T) {
+	snapshot, err := LoadSnapshot(filepath.Join("examples", "cerebro-api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	audit := auditCatalog(snapshot, "", "")
+
+	// Project 947 declares an Unknown tier and release state and links two archived repositories
+	for check, want := range map[string]int{"unknown_tier": 1, "unknown_release_state": 1, "archived_repository": 2} {
+		findings := audit.Findings[check]
+		if len(findings) != want || findings[0].Project.ID != 947 {
+			t.Errorf("%s: %d findings, want %d for project 947", check, len(findings), want)
+		}
+	}
+}
+
+func TestCatalogAuditDeletedRecordsAndBlankValues(t *testing.T) {
+	project := testProject(1, "checkout", "Team Checkout", " unknown ")
+	project.ProjectStakeholderOncall = "Checkout On-Call"
+	project.SlackChannel = "   "
+	project.ReleaseState = "GA"
+	project.RepositoriesIDs = []int{100, 101}
+
+	retired := testProject(2, "old-billing", "Team Payments", "Tier 2")
+	retired.DeletedAt = deletedAt()
+	removedDependency := testDependency(1, 1, 2)
+	removedDependency.DeletedAt = deletedAt()
+
+	snapshot := &Snapshot{
+		Projects:            []Project{project, retired},
+		ProjectDependencies: []ProjectDependency{removedDependency},
+		Repositories: []Repository{
+			// Deleted repositories and repositories missing from the catalog are not checked
+			{ID: 100, Name: "checkout", Archived: true, GithubSyncError: true, DeletedAt: deletedAt()},
+		},
+	}
+	audit := auditCatalog(snapshot, "", "")
+
+	if audit.Audited != 1 {
+		t.Errorf("audited %d projects, want 1", audit.Audited)
+	}
+	for _, check := range []string{"missing_slack_channel", "unknown_tier"} {
+		if len(audit.Findings[check]) != 1 {
+			t.Errorf("%s findings = %d, want 1", check, len(audit.Findings[check]))
+		}
+	}
+	for _, check := range []string{"repository_sync_error", "archived_repository", "deleted_provider", "no_repositories"} {
+		if len(audit.Findings[check]) != 0 {
+			t.Errorf("%s findings = %+v, want none", check, audit.Findings[check])
+		}
+	}
+}
+
+func TestCatalogAuditBlankContactsAndOrder(t *testing.T) {
+	zeta := testProject(1, "zeta", "  ", "Tier 1")
+	zeta.ProjectStakeholderOncall = "\t"
+	alpha := testProject(2, "alpha", "", "Tier 1")
+	middle := testProject(3, "middle", "Team Middle", "Tier 1")
+	middle.ProjectStakeholderOncall = " "
+
+	audit := auditCatalog(&Snapshot{Projects: []Project{zeta, alpha, middle}}, "", "")
+
+	// Whitespace-only contacts count as missing, as they do for compliance scope
+	for check, want := range map[string][]string{
+		"missing_owner":  {"alpha", "zeta"},
+		"missing_oncall": {"alpha", "middle", "zeta"},
+	} {
+		var got []string
+		for _, finding := range audit.Findings[check] {
+			got = append(got, finding.Project.Permalink)
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s findings = %v, want %v in permalink order", check, got, want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// MaxCacheEntries caps the responses kept by the response cache
+const MaxCacheEntries = 1000
+
+// responseCache is an in-memory TTL cache of Cerebro API response bodies keyed by URL.
+// Callers decode the body on every hit, so they never share the decoded response.
+type responseCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]cacheEntry
+	// lastSweep is when expired entries were last removed
+	lastSweep time.Time
+}
+
+// cacheEntry holds a cached response body and its expiry time
+type cacheEntry struct {
+	body      []byte
+	expiresAt time.Time
+}
+
+// newResponseCache creates a cache whose entries expire after ttl and that holds at most
+// MaxCacheEntries responses
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{
+		ttl:        ttl,
+		maxEntries: MaxCacheEntries,
+		entries:    make(map[string]cacheEntry),
+		lastSweep:  time.Now(),
+	}
+}
+
+// get returns a copy of the cached response body for key if it has not expired
+func (c *responseCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return append([]byte(nil), entry.body...), true
+}
+
+// set stores a copy of a response body under key. Expired entries are swept once per TTL,
+// and when the cache is full the entry closest to expiry makes room.
+func (c *responseCache) set(key string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(c.lastSweep) >= c.ttl {
+		c.sweep(now)
+	}
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		c.sweep(now)
+		if len(c.entries) >= c.maxEntries {
+			c.evictOldest()
+		}
+	}
+
+	c.entries[key] = cacheEntry{
+		body:      append([]byte(nil), body...),
+		expiresAt: now.Add(c.ttl),
+	}
+}
+
+// sweep removes the entries that expired before now
+func (c *responseCache) sweep(now time.Time) {
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	c.lastSweep = now
+}
+
+// evictOldest removes the entry that expires first
+func (c *responseCache) evictOldest() {
+	oldest := ""
+	for key, entry := range c.entries {
+		if oldest == "" || entry.expiresAt.Before(c.entries[oldest].expiresAt) {
+			oldest = key
+		}
+	}
+	delete(c.entries, oldest)
+}
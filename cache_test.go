@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestResponseCacheExpiry(t *testing.T) {
+	cache := newResponseCache(time.Minute)
+	cache.set("a", []byte("body a"))
+
+	if body, ok := cache.get("a"); !ok || string(body) != "body a" {
+		t.Errorf("get(a) = %q, %t; want the cached body", body, ok)
+	}
+	if _, ok := cache.get("b"); ok {
+		t.Error("expected a miss for a key that was never set")
+	}
+
+	cache.entries["a"] = cacheEntry{body: []byte("body a"), expiresAt: time.Now().Add(-time.Second)}
+	if _, ok := cache.get("a"); ok {
+		t.Error("expected a miss for an expired entry")
+	}
+	if len(cache.entries) != 0 {
+		t.Errorf("expected the expired entry to be removed, %d left", len(cache.entries))
+	}
+}
+
+func TestResponseCacheCopiesBodies(t *testing.T) {
+	cache := newResponseCache(time.Minute)
+	body := []byte("original")
+	cache.set("a", body)
+	body[0] = 'X'
+
+	got, _ := cache.get("a")
+	if string(got) != "original" {
+		t.Errorf("changing the stored slice changed the cache: %q", got)
+	}
+	got[0] = 'Y'
+	if again, _ := cache.get("a"); string(again) != "original" {
+		t.Errorf("changing a returned slice changed the cache: %q", again)
+	}
+}
+
+func TestResponseCacheSweepsExpiredEntries(t *testing.T) {
+	cache := newResponseCache(time.Minute)
+	for i := 0; i < 10; i++ {
+		cache.entries[fmt.Sprintf("old-%d", i)] = cacheEntry{expiresAt: time.Now().Add(-time.Second)}
+	}
+
+	// Expired entries that are never read again are swept once a TTL has passed
+	cache.set("fresh", []byte("body"))
+	if len(cache.entries) != 11 {
+		t.Fatalf("entries = %d, want no sweep before a TTL has passed", len(cache.entries))
+	}
+	cache.lastSweep = time.Now().Add(-time.Minute)
+	cache.set("fresh", []byte("body"))
+	if len(cache.entries) != 1 {
+		t.Errorf("entries = %d, want only the fresh entry after a sweep", len(cache.entries))
+	}
+}
+
+func TestResponseCacheSizeCap(t *testing.T) {
+	cache := newResponseCache(time.Minute)
+	cache.maxEntries = 3
+	for i := 0; i < 5; i++ {
+		cache.set(fmt.Sprintf("key-%d", i), []byte("body"))
+		// Give every entry its own expiry so the oldest is well defined
+		cache.entries[fmt.Sprintf("key-%d", i)] = cacheEntry{body: []byte("body"), expiresAt: time.Now().Add(time.Duration(i+1) * time.Second)}
+	}
+
+	if len(cache.entries) != 3 {
+		t.Fatalf("entries = %d, want the cap of 3", len(cache.entries))
+	}
+	for _, key := range []string{"key-0", "key-1"} {
+		if _, ok := cache.entries[key]; ok {
+			t.Errorf("expected %s to be evicted first", key)
+		}
+	}
+
+	// Replacing an existing entry does not evict another one
+	cache.set("key-4", []byte("new body"))
+	if len(cache.entries) != 3 {
+		t.Errorf("entries = %d after replacing an entry, want 3", len(cache.entries))
+	}
+}
+
+func TestCerebroClientResponseCacheReturnsCopies(t *testing.T) {
+	fake := newFakeCerebro(t)
+	client := NewCerebroClient(fake.URL(), StaticToken(fakeCerebroToken), WithResponseCache(time.Minute))
+	source := NewLiveSource(client)
+
+	project, _, err := source.FindProject(context.Background(), "example-service", false)
+	if err != nil || project == nil {
+		t.Fatalf("FindProject failed: %v", err)
+	}
+	project.Name = "changed by a caller"
+
+	again, _, err := source.FindProject(context.Background(), "example-service", false)
+	if err != nil || again == nil {
+		t.Fatalf("FindProject failed: %v", err)
+	}
+	if again.Name == "changed by a caller" {
+		t.Error("a caller changed the cached project")
+	}
+	if got := fake.requestCount(); got != 1 {
+		t.Errorf("requests = %d, want the second lookup served from the cache", got)
+	}
+}
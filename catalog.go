@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// catalogGraph is the dependency graph of the whole catalog. Soft-deleted projects and
+// dependencies are left out, as are dependencies on or of projects not in the graph.
+type catalogGraph struct {
+	projects map[int]Project
+	// edges maps a project ID to the dependencies it declares, ordered by provider ID
+	edges map[int][]ProjectDependency
+	// ids lists the project IDs in ascending order
+	ids []int
+}
+
+// newCatalogGraph builds the dependency graph of a catalog
+func newCatalogGraph(catalog *Snapshot) *catalogGraph {
+	g := &catalogGraph{
+		projects: make(map[int]Project),
+		edges:    make(map[int][]ProjectDependency),
+	}
+
+	for _, project := range catalog.Projects {
+		if project.DeletedAt == nil {
+			g.projects[project.ID] = project
+			g.ids = append(g.ids, project.ID)
+		}
+	}
+	sort.Ints(g.ids)
+
+	for _, dep := range catalog.ProjectDependencies {
+		// Dependencies on or of deleted and missing projects would be edges to nowhere
+		_, dependentOK := g.projects[dep.DependentProjectID]
+		_, providerOK := g.projects[dep.ProvidingProjectID]
+		if dep.DeletedAt == nil && dependentOK && providerOK {
+			g.edges[dep.DependentProjectID] = append(g.edges[dep.DependentProjectID], dep)
+		}
+	}
+	for _, deps := range g.edges {
+		sort.Slice(deps, func(i, j int) bool { return deps[i].ProvidingProjectID < deps[j].ProvidingProjectID })
+	}
+
+	return g
+}
+
+// edgeCount returns the number of dependencies in the graph
+func (g *catalogGraph) edgeCount() int {
+	count := 0
+	for _, deps := range g.edges {
+		count += len(deps)
+	}
+	return count
+}
+
+// permalink returns the permalink of a project, or a placeholder for unknown IDs
+func (g *catalogGraph) permalink(id int) string {
+	if project, ok := g.projects[id]; ok {
+		return project.Permalink
+	}
+	return fmt.Sprintf("project #%d", id)
+}
+
+// stronglyConnectedComponents returns the components of the graph restricted to the
+// edges accepted by include, using Tarjan's algorithm. Components are returned with
+// their project IDs in ascending order.
+func (g *catalogGraph) stronglyConnectedComponents(include func(ProjectDependency) bool) [][]int {
+	index := make(map[int]int)
+	lowlink := make(map[int]int)
+	onStack := make(map[int]bool)
+	var stack []int
+	var components [][]int
+	next := 0
+
+	var visit func(id int)
+	visit = func(id int) {
+		index[id] = next
+		lowlink[id] = next
+		next++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, dep := range g.edges[id] {
+			if !include(dep) {
+				continue
+			}
+			provider := dep.ProvidingProjectID
+			if _, visited := index[provider]; !visited {
+				visit(provider)
+				lowlink[id] = min(lowlink[id], lowlink[provider])
+			} else if onStack[provider] {
+				lowlink[id] = min(lowlink[id], index[provider])
+			}
+		}
+
+		if lowlink[id] == index[id] {
+			var component []int
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == id {
+					break
+				}
+			}
+			sort.Ints(component)
+			components = append(components, component)
+		}
+	}
+
+	for _, id := range g.ids {
+		if _, visited := index[id]; !visited {
+			visit(id)
+		}
+	}
+	return components
+}
+
+// shortestPath returns the shortest path of project IDs from one project to another
+// using the edges accepted by include, or nil if there is none. A path from a project to
+// itself is a cycle and has at least one edge.
+func (g *catalogGraph) shortestPath(from, to int, include func(ProjectDependency) bool) []int {
+	parent := make(map[int]int)
+	queue := []int{from}
+	visited := map[int]bool{from: from != to}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		for _, dep := range g.edges[id] {
+			provider := dep.ProvidingProjectID
+			if !include(dep) || visited[provider] {
+				continue
+			}
+			visited[provider] = true
+			parent[provider] = id
+
+			if provider == to {
+				path := []int{to}
+				for node := id; node != from; node = parent[node] {
+					path = append(path, node)
+				}
+				path = append(path, from)
+				for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return path
+			}
+			queue = append(queue, provider)
+		}
+	}
+	return nil
+}
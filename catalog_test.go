@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCatalogToolsEmptyCatalog(t *testing.T) {
+	ctx := context.Background()
+	service := NewProjectService(NewSnapshotSource(&Snapshot{}), NewValidator())
+
+	tests := []struct {
+		name string
+		run  func() (string, error)
+		want string
+	}{
+		{
+			name: "criticality violations",
+			run: func() (string, error) {
+				result, err := service.GetCriticalityViolations(ctx, "")
+				if err != nil {
+					return "", err
+				}
+				return result.FormattedText, nil
+			},
+			want: "No violations found.",
+		},
+		{
+			name: "team projects",
+			run: func() (string, error) {
+				result, err := service.GetTeamProjects(ctx, "Team Payments")
+				if err != nil {
+					return "", err
+				}
+				return result.FormattedText, nil
+			},
+			want: `No projects are owned by or on call with "Team Payments".`,
+		},
+		{
+			name: "catalog audit",
+			run: func() (string, error) {
+				result, err := service.GetCatalogAudit(ctx, "", "")
+				if err != nil {
+					return "", err
+				}
+				return result.FormattedText, nil
+			},
+			want: "Audited 0 projects; problems found in 0.",
+		},
+		{
+			name: "compliance scope",
+			run: func() (string, error) {
+				result, err := service.GetComplianceScope(ctx, "")
+				if err != nil {
+					return "", err
+				}
+				return result.FormattedText, nil
+			},
+			want: "## In-Scope Projects (0)\n\nNone.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := tt.run()
+			if err != nil {
+				t.Fatalf("failed on an empty catalog: %v", err)
+			}
+			if !strings.Contains(text, tt.want) {
+				t.Errorf("expected the report to contain %q:\n%s", tt.want, text)
+			}
+		})
+	}
+}
@@ -0,0 +1,531 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Exit codes returned by runCLI
+const (
+	exitOK       = 0
+	exitError    = 1
+	exitUsage    = 2
+	exitNotFound = 3
+)
+
+const cliUsage = `Usage: cerebro-mcp-server <command> [flags]
+
+Commands:
+  serve            Start the MCP server (default when no command is given)
+  version          Print the server version
+  config check     Validate the configuration and print the effective settings
+  tools list       List the tools the server exposes
+  query details    Print the details of a project
+  query deps       Print the dependencies of a project
+  snapshot export  Export the whole Cerebro catalog to a snapshot file
+  snapshot diff    Compare two snapshots, or changes in one snapshot since a date
+
+Run "cerebro-mcp-server <command> --help" for the flags of a command.
+`
+
+// runCLI dispatches the command line to a subcommand and returns the process exit code
+func runCLI(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || (strings.HasPrefix(args[0], "-") && args[0] != "-h" && args[0] != "--help") {
+		// Keep "cerebro-mcp-server [flags]" working for existing client configs
+		return runServe(args, stderr)
+	}
+
+	switch args[0] {
+	case "serve":
+		return runServe(args[1:], stderr)
+	case "version":
+		fmt.Fprintf(stdout, "cerebro-mcp-server %s\n", version)
+		return exitOK
+	case "config":
+		if len(args) < 2 || args[1] != "check" {
+			fmt.Fprint(stderr, "Usage: cerebro-mcp-server config check [flags]\n")
+			return exitUsage
+		}
+		return runConfigCheck(args[2:], stdout, stderr)
+	case "tools":
+		if len(args) < 2 || args[1] != "list" {
+			fmt.Fprint(stderr, "Usage: cerebro-mcp-server tools list [flags]\n")
+			return exitUsage
+		}
+		return runToolsList(args[2:], stdout, stderr)
+	case "query":
+		if len(args) < 2 || (args[1] != "details" && args[1] != "deps") {
+			fmt.Fprint(stderr, "Usage: cerebro-mcp-server query details|deps <permalink> [flags]\n")
+			return exitUsage
+		}
+		return runQuery(args[1], args[2:], stdout, stderr)
+	case "snapshot":
+		if len(args) < 2 || (args[1] != "export" && args[1] != "diff") {
+			fmt.Fprint(stderr, "Usage: cerebro-mcp-server snapshot export|diff [flags]\n")
+			return exitUsage
+		}
+		if args[1] == "diff" {
+			return runSnapshotDiff(args[2:], stdout, stderr)
+		}
+		return runSnapshotExport(args[2:], stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, cliUsage)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "Unknown command %q\n\n%s", args[0], cliUsage)
+		return exitUsage
+	}
+}
+
+// newFlagSet creates a flag set for a subcommand with the shared configuration flags
+func newFlagSet(name, summary string, stderr io.Writer) (*flag.FlagSet, *ConfigFlags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configFlags := BindConfigFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: cerebro-mcp-server %s [flags]\n\n%s\n\nFlags:\n", name, summary)
+		fs.PrintDefaults()
+	}
+	return fs, configFlags
+}
+
+// parseFlags parses args and returns the exit code to use if parsing did not succeed
+func parseFlags(fs *flag.FlagSet, args []string) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK, false
+		}
+		return exitUsage, false
+	}
+	return exitOK, true
+}
+
+// parseFlagsWithArgs parses args allowing flags before and after positional
+// arguments, and returns the positional arguments
+func parseFlagsWithArgs(fs *flag.FlagSet, args []string) ([]string, int, bool) {
+	var positional []string
+	for {
+		if code, ok := parseFlags(fs, args); !ok {
+			return nil, code, false
+		}
+		if fs.NArg() == 0 {
+			return positional, exitOK, true
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// newProjectSourceFromConfig creates the live or snapshot data source from configuration
+func newProjectSourceFromConfig(config *Config) (ProjectSource, error) {
+	if config.SnapshotPath != "" {
+		snapshot, err := LoadSnapshot(config.SnapshotPath)
+		if err != nil {
+			return nil, err
+		}
+		return NewSnapshotSource(snapshot, WithSourceName("snapshot "+config.SnapshotPath)), nil
+	}
+
+	client, err := newCerebroClientFromConfig(config, config.CacheEnabled)
+	if err != nil {
+		return nil, err
+	}
+	return NewLiveSource(client, WithCatalogTTL(config.CatalogTTL)), nil
+}
+
+// newCerebroClientFromConfig creates a Cerebro API client, optionally with the response cache
+func newCerebroClientFromConfig(config *Config, withCache bool) (*CerebroClient, error) {
+	if err := config.RequireCredentials(); err != nil {
+		return nil, err
+	}
+
+	clientOpts := []ClientOption{WithHTTPTimeout(config.HTTPTimeout)}
+	if withCache {
+		clientOpts = append(clientOpts, WithResponseCache(config.CacheTTL))
+	}
+	return NewCerebroClient(config.CerebroAPIBaseURL, config.TokenSource(), clientOpts...), nil
+}
+
+// newProjectServiceFromConfig wires the data source and service from configuration
+func newProjectServiceFromConfig(config *Config) (*ProjectService, error) {
+	source, err := newProjectSourceFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return NewProjectService(source, NewValidator(),
+		WithMaxConcurrency(config.MaxConcurrency),
+		WithSnapshotDir(config.SnapshotDir),
+		WithResponseBudget(config.MaxResponseChars),
+	), nil
+}
+
+// newProjectServerFromConfig wires the client, service and server from configuration
+func newProjectServerFromConfig(config *Config) (*ProjectServer, error) {
+	service, err := newProjectServiceFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return NewProjectServer(service, service.validator, config.EnabledTools), nil
+}
+
+// runServe starts the server on the configured transport
+func runServe(args []string, stderr io.Writer) int {
+	fs, configFlags := newFlagSet("serve", "Start the MCP server on the stdio, http or sse transport.", stderr)
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	config, err := LoadConfig(configFlags)
+	if err != nil {
+		log.Printf("Failed to load configuration: %v", err)
+		return exitError
+	}
+
+	projectServer, err := newProjectServerFromConfig(config)
+	if err != nil {
+		log.Printf("Failed to load configuration: %v", err)
+		return exitError
+	}
+
+	switch config.Transport {
+	case TransportHTTP:
+		err = startHTTPServer(projectServer, config)
+	case TransportSSE:
+		err = startSSEServer(projectServer.SetupMCPServer(), config)
+	default:
+		err = startStdioServer(projectServer.SetupMCPServer())
+	}
+	if err != nil {
+		log.Printf("Server error: %v", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// runConfigCheck validates the configuration and prints the effective settings
+func runConfigCheck(args []string, stdout, stderr io.Writer) int {
+	fs, configFlags := newFlagSet("config check", "Validate the configuration and print the effective settings.", stderr)
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	config, problems := loadConfig(configFlags)
+	problems = append(problems, config.validateCredentials()...)
+	if len(problems) > 0 {
+		fmt.Fprintln(stderr, (&ConfigError{Problems: problems}).Error())
+		return exitError
+	}
+
+	token := "CEREBRO_TOKEN (value hidden)"
+	switch {
+	case config.CerebroTokenFile != "":
+		token = "file " + config.CerebroTokenFile
+	case config.CerebroTokenCommand != "":
+		token = "command " + config.CerebroTokenCommand
+	}
+
+	fmt.Fprintf(stdout, "Configuration OK\n\n")
+	if config.SnapshotPath != "" {
+		fmt.Fprintf(stdout, "Data source:      snapshot %s\n", config.SnapshotPath)
+	} else {
+		fmt.Fprintf(stdout, "Data source:      live Cerebro API\n")
+		fmt.Fprintf(stdout, "Cerebro API URL:  %s\n", config.CerebroAPIBaseURL)
+		fmt.Fprintf(stdout, "Cerebro token:    %s\n", token)
+	}
+	if config.SnapshotDir != "" {
+		fmt.Fprintf(stdout, "Snapshot dir:     %s\n", config.SnapshotDir)
+	}
+	fmt.Fprintf(stdout, "Request timeout:  %s\n", config.HTTPTimeout)
+	fmt.Fprintf(stdout, "Max concurrency:  %d\n", config.MaxConcurrency)
+	if config.MaxResponseChars > 0 {
+		fmt.Fprintf(stdout, "Response budget:  %d characters\n", config.MaxResponseChars)
+	} else {
+		fmt.Fprintf(stdout, "Response budget:  unlimited\n")
+	}
+	if config.CacheEnabled {
+		fmt.Fprintf(stdout, "Response cache:   enabled (TTL %s)\n", config.CacheTTL)
+	} else {
+		fmt.Fprintf(stdout, "Response cache:   disabled\n")
+	}
+	if config.CatalogTTL > 0 {
+		fmt.Fprintf(stdout, "Catalog cache:    %s\n", config.CatalogTTL)
+	} else {
+		fmt.Fprintf(stdout, "Catalog cache:    disabled\n")
+	}
+	fmt.Fprintf(stdout, "Transport:        %s\n", config.Transport)
+	fmt.Fprintf(stdout, "HTTP address:     %s\n", config.ServerPort)
+	fmt.Fprintf(stdout, "HTTP endpoint:    %s\n", config.MCPEndpoint)
+	fmt.Fprintf(stdout, "Enabled tools:    %s\n", strings.Join(config.EnabledTools, ", "))
+	return exitOK
+}
+
+// runToolsList prints the tools exposed by the server with the current configuration
+func runToolsList(args []string, stdout, stderr io.Writer) int {
+	fs, configFlags := newFlagSet("tools list", "List the tools the server exposes with the current configuration.", stderr)
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	config, err := LoadConfig(configFlags)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	projectServer := NewProjectServer(nil, nil, config.EnabledTools)
+	for _, def := range projectServer.enabledToolDefinitions() {
+		fmt.Fprintf(stdout, "%s\n    %s\n", def.tool.Name, def.tool.Description)
+		for _, name := range slices.Sorted(maps.Keys(def.tool.InputSchema.Properties)) {
+			required := ""
+			for _, r := range def.tool.InputSchema.Required {
+				if r == name {
+					required = " (required)"
+				}
+			}
+			fmt.Fprintf(stdout, "    - %s%s\n", name, required)
+		}
+	}
+	return exitOK
+}
+
+// runQuery calls ProjectService directly and prints the result for humans and scripts
+func runQuery(kind string, args []string, stdout, stderr io.Writer) int {
+	summary := "Print the details of a project as markdown or JSON."
+	if kind == "deps" {
+		summary = "Print the dependencies of a project as markdown or JSON."
+	}
+	fs, configFlags := newFlagSet("query "+kind+" <permalink>", summary, stderr)
+	format := fs.String("format", "markdown", "output format: markdown or json")
+	var fields string
+	if kind == "details" {
+		fs.StringVar(&fields, "fields", "", "comma-separated sections to print (default all): "+strings.Join(detailsFields, ", "))
+	}
+	var opts DependencyOptions
+	if kind == "deps" {
+		fs.BoolVar(&opts.IncludeDeleted, "include-deleted", false, "also list soft-deleted dependencies")
+		fs.StringVar(&opts.Optional, "optional", DependencyFilterAll, "list all, required or optional dependencies")
+		fs.StringVar(&opts.GroupBy, "group-by", DependencyGroupByNone, "group by none, category, tier, owner or optional")
+		fs.IntVar(&opts.Limit, "limit", 0, "maximum number of dependencies to print, 0 for all")
+		fs.StringVar(&opts.Cursor, "cursor", "", "continue a listing where the previous page stopped")
+	}
+	positional, code, ok := parseFlagsWithArgs(fs, args)
+	if !ok {
+		return code
+	}
+	if len(positional) != 1 {
+		fmt.Fprintf(stderr, "Usage: cerebro-mcp-server query %s <permalink> [flags]\n", kind)
+		return exitUsage
+	}
+	if *format != "markdown" && *format != "json" {
+		fmt.Fprintf(stderr, "Unknown format %q: use markdown or json\n", *format)
+		return exitUsage
+	}
+	if opts.Limit < 0 {
+		fmt.Fprintf(stderr, "Invalid limit %d: must not be negative\n", opts.Limit)
+		return exitUsage
+	}
+	if kind == "deps" && !slices.Contains([]string{DependencyFilterAll, DependencyFilterRequired, DependencyFilterOptional}, opts.Optional) {
+		fmt.Fprintf(stderr, "Unknown optional filter %q: use all, required or optional\n", opts.Optional)
+		return exitUsage
+	}
+	if kind == "deps" && !slices.Contains([]string{DependencyGroupByNone, DependencyGroupByCategory, DependencyGroupByTier, DependencyGroupByOwner, DependencyGroupByOptional}, opts.GroupBy) {
+		fmt.Fprintf(stderr, "Unknown grouping %q: use none, category, tier, owner or optional\n", opts.GroupBy)
+		return exitUsage
+	}
+	permalink := positional[0]
+
+	config, err := LoadConfig(configFlags)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	if *format == "json" {
+		// The response budget protects agent context windows; scripts get everything
+		config.MaxResponseChars = 0
+	}
+	service, err := newProjectServiceFromConfig(config)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	ctx := context.Background()
+	var text string
+	var data interface{}
+	switch kind {
+	case "details":
+		result, err := service.GetProjectDetails(ctx, permalink, splitList(fields))
+		if err != nil {
+			return reportQueryError(err, stderr)
+		}
+		text, data = result.FormattedText, result.Project
+	case "deps":
+		result, err := service.GetProjectDependencies(ctx, permalink, opts)
+		if err != nil {
+			return reportQueryError(err, stderr)
+		}
+		text = result.FormattedText
+		data = struct {
+			Project      Project            `json:"project"`
+			Dependencies []DependencyDetail `json:"dependencies"`
+			NextCursor   string             `json:"next_cursor,omitempty"`
+		}{result.Project, result.Dependencies, result.NextCursor}
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(data); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	fmt.Fprint(stdout, text)
+	return exitOK
+}
+
+// reportQueryError prints a query failure and returns the matching exit code
+func reportQueryError(err error, stderr io.Writer) int {
+	fmt.Fprintln(stderr, toolErrorMessage(err))
+
+	var notFoundErr *ProjectNotFoundError
+	if errors.As(err, &notFoundErr) {
+		return exitNotFound
+	}
+	return exitError
+}
+
+// runSnapshotExport pages through the Cerebro catalog and writes a compressed snapshot
+func runSnapshotExport(args []string, stdout, stderr io.Writer) int {
+	fs, configFlags := newFlagSet("snapshot export", "Export every project, dependency and repository to a gzip-compressed snapshot.\nAn interrupted export resumes from its checkpoint when re-run with the same output.", stderr)
+	output := fs.String("output", "", "snapshot file to write, e.g. cerebro-2025-01-31.json.gz (required)")
+	pageSize := fs.Int("page-size", DefaultExportPageSize, "projects requested per page")
+	rate := fs.Float64("rate", DefaultExportRate, "maximum Cerebro requests per second")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if *output == "" || *pageSize < 1 || *rate <= 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	config, err := LoadConfig(configFlags)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	client, err := newCerebroClientFromConfig(config, false)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	logf := func(format string, args ...interface{}) {
+		fmt.Fprintf(stderr, format+"\n", args...)
+	}
+	// Stop between pages on Ctrl-C so the checkpoint stays consistent
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	snapshot, err := NewSnapshotExporter(client, *pageSize, *rate, logf).Export(ctx, *output)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	fmt.Fprintf(stdout, "Wrote %s: %d projects, %d dependencies, %d repositories\n",
+		*output, len(snapshot.Projects), len(snapshot.ProjectDependencies), len(snapshot.Repositories))
+	return exitOK
+}
+
+// runSnapshotDiff prints the changes between two snapshots, or the changes recorded in
+// one snapshot since a point in time
+func runSnapshotDiff(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("snapshot diff", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	since := fs.String("since", "", "report changes recorded in the snapshot after this RFC 3339 time or YYYY-MM-DD date")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: cerebro-mcp-server snapshot diff <from> <to>\n       cerebro-mcp-server snapshot diff --since <date> <snapshot>\n\nCompare catalog snapshots.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	positional, code, ok := parseFlagsWithArgs(fs, args)
+	if !ok {
+		return code
+	}
+	if (*since == "" && len(positional) != 2) || (*since != "" && len(positional) != 1) {
+		fs.Usage()
+		return exitUsage
+	}
+
+	var diff *CatalogDiff
+	if *since != "" {
+		sinceTime, err := parseSince(*since)
+		if err != nil {
+			fmt.Fprintf(stderr, "Invalid --since %q: use an RFC 3339 time or a YYYY-MM-DD date\n", *since)
+			return exitUsage
+		}
+		snapshot, err := LoadSnapshot(positional[0])
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		diff = DiffSince(filepath.Base(positional[0]), snapshot, sinceTime)
+	} else {
+		from, err := LoadSnapshot(positional[0])
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		to, err := LoadSnapshot(positional[1])
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		diff = DiffSnapshots(filepath.Base(positional[0]), from, filepath.Base(positional[1]), to)
+	}
+
+	fmt.Fprint(stdout, formatCatalogDiff(diff))
+	return exitOK
+}
+
+func startHTTPServer(projectServer *ProjectServer, config *Config) error {
+	// Start HTTP server
+	mux := http.NewServeMux()
+	mux.Handle(config.MCPEndpoint, projectServer)
+	log.Printf("Project MCP Server starting HTTP mode on %s", config.ServerPort)
+	log.Printf("Send POST requests to http://localhost%s%s", config.ServerPort, config.MCPEndpoint)
+	log.Printf("Available tools: %s", strings.Join(config.EnabledTools, ", "))
+	log.Printf("Example request body: {\"tool\": \"%s\", \"arguments\": {\"project_permalink\": \"your-project\"}}", ToolProjectGetDetails)
+	log.Printf("Example dependencies request: {\"tool\": \"%s\", \"arguments\": {\"project_permalink\": \"your-project\"}}", ToolProjectGetDependencies)
+	return http.ListenAndServe(config.ServerPort, mux)
+}
+
+func startSSEServer(mcpServer *server.MCPServer, config *Config) error {
+	// Start SSE server for MCP clients that connect over the network
+	log.Printf("Project MCP Server starting SSE mode on %s", config.ServerPort)
+	log.Printf("MCP clients can connect to http://localhost%s/sse", config.ServerPort)
+	return server.NewSSEServer(mcpServer).Start(config.ServerPort)
+}
+
+func startStdioServer(mcpServer *server.MCPServer) error {
+	// Start stdio server (default mode)
+	log.Printf("Project MCP Server starting in stdio mode")
+	log.Printf("Use \"serve --transport http\" or \"serve --transport sse\" to serve over the network instead")
+	return server.ServeStdio(mcpServer)
+}
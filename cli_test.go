@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCLI(t *testing.T) {
+	snapshot := filepath.Join("examples", "cerebro-api")
+	output := filepath.Join(t.TempDir(), "catalog.json.gz")
+
+	tests := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		failWith int
+		wantCode int
+		// wantStdout and wantStderr are expected substrings; empty means no output at all
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "version",
+			args:       []string{"version"},
+			wantCode:   exitOK,
+			wantStdout: "cerebro-mcp-server " + version,
+		},
+		{
+			name:       "help",
+			args:       []string{"help"},
+			wantCode:   exitOK,
+			wantStdout: "Usage: cerebro-mcp-server <command>",
+		},
+		{
+			name:       "unknown command",
+			args:       []string{"deploy"},
+			wantCode:   exitUsage,
+			wantStderr: `Unknown command "deploy"`,
+		},
+		{
+			name:       "config check",
+			args:       []string{"config", "check"},
+			wantCode:   exitOK,
+			wantStdout: "Configuration OK",
+		},
+		{
+			name:       "config check with an invalid configuration",
+			args:       []string{"config", "check", "-transport", "carrier-pigeon"},
+			wantCode:   exitError,
+			wantStderr: `transport "carrier-pigeon" must be one of stdio, http or sse`,
+		},
+		{
+			name:       "config check without a token",
+			args:       []string{"config", "check"},
+			env:        map[string]string{"CEREBRO_TOKEN": ""},
+			wantCode:   exitError,
+			wantStderr: "a Cerebro token is required",
+		},
+		{
+			name:       "config without check",
+			args:       []string{"config"},
+			wantCode:   exitUsage,
+			wantStderr: "Usage: cerebro-mcp-server config check",
+		},
+		{
+			name:       "config check with an unknown flag",
+			args:       []string{"config", "check", "-colour"},
+			wantCode:   exitUsage,
+			wantStderr: "flag provided but not defined: -colour",
+		},
+		{
+			name:       "tools list",
+			args:       []string{"tools", "list", "-tools", ToolProjectGetDetails},
+			wantCode:   exitOK,
+			wantStdout: ToolProjectGetDetails + "\n",
+		},
+		{
+			name:       "tools list with an unknown tool",
+			args:       []string{"tools", "list", "-tools", "project_teleport"},
+			wantCode:   exitError,
+			wantStderr: `unknown tool "project_teleport"`,
+		},
+		{
+			name:       "tools without list",
+			args:       []string{"tools", "show"},
+			wantCode:   exitUsage,
+			wantStderr: "Usage: cerebro-mcp-server tools list",
+		},
+		{
+			name:       "query details",
+			args:       []string{"query", "details", "example-service"},
+			wantCode:   exitOK,
+			wantStdout: "# Project Details for: example-service",
+		},
+		{
+			name:       "query details as JSON",
+			args:       []string{"query", "details", "example-service", "--format", "json"},
+			wantCode:   exitOK,
+			wantStdout: `"permalink": "example-service"`,
+		},
+		{
+			name:       "query deps",
+			args:       []string{"query", "deps", "example-service"},
+			wantCode:   exitOK,
+			wantStdout: "## Dependencies (83)",
+		},
+		{
+			name:       "query deps as JSON with a limit",
+			args:       []string{"query", "deps", "--format", "json", "--limit", "1", "example-service"},
+			wantCode:   exitOK,
+			wantStdout: `"next_cursor": "`,
+		},
+		{
+			name:       "query details of an unknown project",
+			args:       []string{"query", "details", "no-such-project"},
+			wantCode:   exitNotFound,
+			wantStderr: `Project "no-such-project" was not found in Cerebro`,
+		},
+		{
+			name:       "query deps of an unknown project",
+			args:       []string{"query", "deps", "no-such-project"},
+			wantCode:   exitNotFound,
+			wantStderr: `Project "no-such-project" was not found in Cerebro`,
+		},
+		{
+			name:       "query details of an unknown project in a snapshot",
+			args:       []string{"query", "details", "no-such-project", "-snapshot", snapshot},
+			wantCode:   exitNotFound,
+			wantStderr: `Project "no-such-project" was not found in snapshot ` + snapshot + ".",
+		},
+		{
+			name:       "query details without a permalink",
+			args:       []string{"query", "details"},
+			wantCode:   exitUsage,
+			wantStderr: "Usage: cerebro-mcp-server query details <permalink>",
+		},
+		{
+			name:       "query with an unknown kind",
+			args:       []string{"query", "owners", "example-service"},
+			wantCode:   exitUsage,
+			wantStderr: "Usage: cerebro-mcp-server query details|deps",
+		},
+		{
+			name:       "query deps with an unknown format",
+			args:       []string{"query", "deps", "example-service", "--format", "xml"},
+			wantCode:   exitUsage,
+			wantStderr: `Unknown format "xml"`,
+		},
+		{
+			name:       "query deps with an unknown grouping",
+			args:       []string{"query", "deps", "example-service", "--group-by", "colour"},
+			wantCode:   exitUsage,
+			wantStderr: `Unknown grouping "colour"`,
+		},
+		{
+			name:       "query deps with a negative limit",
+			args:       []string{"query", "deps", "example-service", "--limit", "-1"},
+			wantCode:   exitUsage,
+			wantStderr: "Invalid limit -1",
+		},
+		{
+			name:       "query details while Cerebro is down",
+			args:       []string{"query", "details", "example-service"},
+			failWith:   http.StatusServiceUnavailable,
+			wantCode:   exitError,
+			wantStderr: "Cerebro is currently unavailable (HTTP 503)",
+		},
+		{
+			name:       "query deps with an invalid cursor",
+			args:       []string{"query", "deps", "example-service", "--cursor", "bogus"},
+			wantCode:   exitError,
+			wantStderr: `Invalid argument "cursor"`,
+		},
+		{
+			name:       "query details with an invalid configuration",
+			args:       []string{"query", "details", "example-service", "-max-concurrency", "0"},
+			wantCode:   exitError,
+			wantStderr: "max concurrency must be at least 1",
+		},
+		{
+			name:       "snapshot export",
+			args:       []string{"snapshot", "export", "--output", output, "--rate", "1000"},
+			wantCode:   exitOK,
+			wantStdout: "Wrote " + output,
+			wantStderr: "Fetched page 1",
+		},
+		{
+			name:       "snapshot export without an output",
+			args:       []string{"snapshot", "export"},
+			wantCode:   exitUsage,
+			wantStderr: "Usage: cerebro-mcp-server snapshot export",
+		},
+		{
+			name:       "snapshot export while Cerebro is down",
+			args:       []string{"snapshot", "export", "--output", filepath.Join(t.TempDir(), "catalog.json.gz"), "--rate", "1000"},
+			failWith:   http.StatusServiceUnavailable,
+			wantCode:   exitError,
+			wantStderr: "503",
+		},
+		{
+			name:       "snapshot diff",
+			args:       []string{"snapshot", "diff", snapshot, snapshot},
+			wantCode:   exitOK,
+			wantStdout: "cerebro-api",
+		},
+		{
+			name:       "snapshot diff with one snapshot",
+			args:       []string{"snapshot", "diff", snapshot},
+			wantCode:   exitUsage,
+			wantStderr: "Usage: cerebro-mcp-server snapshot diff <from> <to>",
+		},
+		{
+			name:       "snapshot diff with a missing snapshot",
+			args:       []string{"snapshot", "diff", snapshot, filepath.Join(t.TempDir(), "missing.json")},
+			wantCode:   exitError,
+			wantStderr: "missing.json",
+		},
+		{
+			name:       "snapshot diff with an invalid date",
+			args:       []string{"snapshot", "diff", "--since", "yesterday", snapshot},
+			wantCode:   exitUsage,
+			wantStderr: `Invalid --since "yesterday"`,
+		},
+		{
+			name:       "snapshot without a subcommand",
+			args:       []string{"snapshot"},
+			wantCode:   exitUsage,
+			wantStderr: "Usage: cerebro-mcp-server snapshot export|diff",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeCerebro(t)
+			fake.failWith(tt.failWith)
+
+			for _, key := range configEnvVars {
+				t.Setenv(key, "")
+			}
+			t.Setenv("CEREBRO_TOKEN", fakeCerebroToken)
+			t.Setenv("CEREBRO_API_BASE_URL", fake.URL())
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			var stdout, stderr bytes.Buffer
+			code := runCLI(tt.args, &stdout, &stderr)
+
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d\nstdout: %s\nstderr: %s", code, tt.wantCode, stdout.String(), stderr.String())
+			}
+			assertCLIOutput(t, "stdout", stdout.String(), tt.wantStdout)
+			assertCLIOutput(t, "stderr", stderr.String(), tt.wantStderr)
+		})
+	}
+
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("snapshot export did not write its output: %v", err)
+	}
+}
+
+// assertCLIOutput checks that a CLI output stream contains want, or is empty when want is
+func assertCLIOutput(t *testing.T, stream, got, want string) {
+	t.Helper()
+
+	switch {
+	case want == "" && got != "":
+		t.Errorf("expected no %s, got:\n%s", stream, got)
+	case want != "" && !strings.Contains(got, want):
+		t.Errorf("%s does not contain %q:\n%s", stream, want, got)
+	}
+}
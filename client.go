@@ -3,10 +3,12 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -14,22 +16,54 @@ import (
 type CerebroClient struct {
 	baseURL    string
 	httpClient *http.Client
-	token      string
+	tokens     TokenSource
+	cache      *responseCache
+}
+
+// ClientOption configures optional CerebroClient behavior
+type ClientOption func(*CerebroClient)
+
+// WithHTTPTimeout sets the timeout for each Cerebro API request
+func WithHTTPTimeout(timeout time.Duration) ClientOption {
+	return func(c *CerebroClient) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithResponseCache caches successful API responses in memory for ttl
+func WithResponseCache(ttl time.Duration) ClientOption {
+	return func(c *CerebroClient) {
+		c.cache = newResponseCache(ttl)
+	}
 }
 
 // NewCerebroClient creates a new Cerebro API client
-func NewCerebroClient(baseURL, token string) *CerebroClient {
-	return &CerebroClient{
+func NewCerebroClient(baseURL string, tokens TokenSource, opts ...ClientOption) *CerebroClient {
+	client := &CerebroClient{
 		baseURL:    baseURL,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
-		token:      token,
+		httpClient: &http.Client{Timeout: DefaultHTTPTimeout},
+		tokens:     tokens,
+	}
+	for _, opt := range opts {
+		opt(client)
 	}
+	return client
 }
 
 // buildURL builds the API URL with the given parameters
 func (c *CerebroClient) buildURL(params CerebroAPIParameters) string {
 	urlParams := url.Values{}
-	urlParams.Set(fmt.Sprintf("search[%s]", params.searchKey), params.searchValue)
+	if params.searchKey != "" {
+		urlParams.Set(fmt.Sprintf("search[%s]", params.searchKey), params.searchValue)
+	}
+
+	if params.page > 0 {
+		urlParams.Set("page", strconv.Itoa(params.page))
+	}
+
+	if params.perPage > 0 {
+		urlParams.Set("per_page", strconv.Itoa(params.perPage))
+	}
 
 	if len(params.includes) != 0 {
 		urlParams.Add("includes", params.includes)
@@ -44,12 +78,61 @@ func (c *CerebroClient) buildURL(params CerebroAPIParameters) string {
 
 // makeRequest makes an authenticated HTTP request to the Cerebro API
 func (c *CerebroClient) makeRequest(ctx context.Context, apiURL string) (*APIResponse, error) {
+	if c.cache != nil {
+		if cached, ok := c.cache.get(apiURL); ok {
+			return decodeAPIResponse(cached)
+		}
+	}
+
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.get(ctx, apiURL, token)
+
+	// A 401 usually means the token was rotated; reload it and retry once if it changed
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+		c.tokens.Invalidate()
+		refreshed, tokenErr := c.tokens.Token(ctx)
+		if tokenErr == nil && refreshed != token {
+			body, err = c.get(ctx, apiURL, refreshed)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	apiResponse, err := decodeAPIResponse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.cache != nil {
+		c.cache.set(apiURL, body)
+	}
+
+	return apiResponse, nil
+}
+
+// decodeAPIResponse parses the body of a Cerebro API response
+func decodeAPIResponse(body []byte) (*APIResponse, error) {
+	var apiResponse APIResponse
+	if err := json.Unmarshal(body, &apiResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+	return &apiResponse, nil
+}
+
+// get performs a single authenticated GET request and returns the response body
+func (c *CerebroClient) get(ctx context.Context, apiURL, token string) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Token "+c.token)
+	req.Header.Set("Authorization", "Token "+token)
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := c.httpClient.Do(req)
@@ -70,10 +153,5 @@ func (c *CerebroClient) makeRequest(ctx context.Context, apiURL string) (*APIRes
 		}
 	}
 
-	var apiResponse APIResponse
-	if err := json.Unmarshal(body, &apiResponse); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
-	}
-
-	return &apiResponse, nil
+	return body, nil
 }
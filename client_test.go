@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCerebroClientBuildURL(t *testing.T) {
+	client := NewCerebroClient("https://cerebro.example.com/projects.json", StaticToken("token"))
+
+	apiURL := client.buildURL(CerebroAPIParameters{
+		searchKey:   "permalink",
+		searchValue: "classic",
+		inlines:     "project_repository_urls",
+		includes:    "dependent_project_dependencies",
+	})
+
+	parsed, err := url.Parse(apiURL)
+	if err != nil {
+		t.Fatalf("buildURL returned an invalid URL %q: %v", apiURL, err)
+	}
+	query := parsed.Query()
+	if got := query.Get("search[permalink]"); got != "classic" {
+		t.Errorf("search[permalink] = %q, want %q", got, "classic")
+	}
+	if got := query.Get("inlines"); got != "project_repository_urls" {
+		t.Errorf("inlines = %q, want %q", got, "project_repository_urls")
+	}
+	if got := query.Get("includes"); got != "dependent_project_dependencies" {
+		t.Errorf("includes = %q, want %q", got, "dependent_project_dependencies")
+	}
+}
+
+func TestCerebroClientMakeRequest(t *testing.T) {
+	fake := newFakeCerebro(t)
+	client := NewCerebroClient(fake.URL(), StaticToken(fakeCerebroToken))
+
+	response, err := client.makeRequest(context.Background(), client.buildURL(CerebroAPIParameters{
+		searchKey:   "id",
+		searchValue: "947",
+	}))
+	if err != nil {
+		t.Fatalf("makeRequest failed: %v", err)
+	}
+	if len(response.Projects) != 1 || response.Projects[0].ID != 947 {
+		t.Fatalf("expected project 947, got %+v", response.Projects)
+	}
+	if len(response.Projects[0].ProjectRepositoryURLs) != 0 {
+		t.Errorf("expected inline fields to be omitted when not requested")
+	}
+}
+
+func TestCerebroClientAPIErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		status int
+		want   int
+	}{
+		{name: "unauthorized", token: "wrong-token", want: http.StatusUnauthorized},
+		{name: "rate limited", token: fakeCerebroToken, status: http.StatusTooManyRequests, want: http.StatusTooManyRequests},
+		{name: "server error", token: fakeCerebroToken, status: http.StatusInternalServerError, want: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeCerebro(t)
+			fake.failWith(tt.status)
+			client := NewCerebroClient(fake.URL(), StaticToken(tt.token))
+
+			_, err := client.makeRequest(context.Background(), client.buildURL(CerebroAPIParameters{
+				searchKey:   "id",
+				searchValue: "947",
+			}))
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected APIError, got %v", err)
+			}
+			if apiErr.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", apiErr.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestCerebroClientResponseCache(t *testing.T) {
+	fake := newFakeCerebro(t)
+	client := NewCerebroClient(fake.URL(), StaticToken(fakeCerebroToken), WithResponseCache(time.Minute))
+	apiURL := client.buildURL(CerebroAPIParameters{searchKey: "id", searchValue: "947"})
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.makeRequest(context.Background(), apiURL); err != nil {
+			t.Fatalf("makeRequest failed: %v", err)
+		}
+	}
+
+	if got := fake.requestCount(); got != 1 {
+		t.Errorf("expected 1 upstream request with the cache enabled, got %d", got)
+	}
+}
+
+func TestCerebroClientReloadsRotatedTokenOn401(t *testing.T) {
+	fake := newFakeCerebro(t)
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte(fakeCerebroToken+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewCerebroClient(fake.URL(), NewFileTokenSource(tokenFile))
+	apiURL := client.buildURL(CerebroAPIParameters{searchKey: "id", searchValue: "947"})
+	if _, err := client.makeRequest(context.Background(), apiURL); err != nil {
+		t.Fatalf("makeRequest failed: %v", err)
+	}
+
+	// Rotate the secret upstream and on disk; keep the file metadata identical so only
+	// the 401 triggers the reload
+	info, err := os.Stat(tokenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.setToken("rotatedtok")
+	if err := os.WriteFile(tokenFile, []byte("rotatedtok\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(tokenFile, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.makeRequest(context.Background(), apiURL); err != nil {
+		t.Fatalf("expected the rotated token to be picked up after a 401, got %v", err)
+	}
+}
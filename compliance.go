@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ComplianceProvider is a required dependency of an in-scope project on a provider that is
+// out of scope. Provider is nil when the provider is deleted or missing from the catalog.
+type ComplianceProvider struct {
+	Dependency ProjectDependency
+	Provider   *Project
+}
+
+// ComplianceProject is a project in compliance scope and the problems found with it
+type ComplianceProject struct {
+	Project Project
+	// OutOfScopeProviders are the providers of its required dependencies that are not in scope
+	OutOfScopeProviders []ComplianceProvider
+	MissingOwner        bool
+	MissingOncall       bool
+}
+
+// flagged reports whether the project has any compliance problem
+func (p ComplianceProject) flagged() bool {
+	return len(p.OutOfScopeProviders) > 0 || p.MissingOwner || p.MissingOncall
+}
+
+// ComplianceScope is the result of a compliance scope report
+type ComplianceScope struct {
+	Team string
+	// Projects are the in-scope projects, most critical first
+	Projects []ComplianceProject
+	// UnknownSOC2 counts out-of-scope projects whose in_scope_for_soc2 is not set
+	UnknownSOC2 int
+}
+
+// inComplianceScope reports whether a project is in SOC2 scope or required for compliance
+func inComplianceScope(project Project) bool {
+	return project.InScopeForSOC2.Yes() || project.RequiredForCompliance
+}
+
+// GetComplianceScope loads the whole catalog and lists the projects in compliance scope
+// that are owned by or on call with team; an empty team matches every project
+func (s *ProjectService) GetComplianceScope(ctx context.Context, team string) (*ComplianceScopeResult, error) {
+	catalog, err := s.source.Catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := complianceScope(catalog, team)
+	return &ComplianceScopeResult{
+		Scope:         scope,
+		FormattedText: formatComplianceScope(scope),
+	}, nil
+}
+
+// complianceScope collects the in-scope projects of the catalog and checks each of them.
+// Dependencies are read from the catalog rather than the graph, which leaves out those on
+// deleted and missing projects: an auditor needs to see them.
+func complianceScope(catalog *Snapshot, team string) *ComplianceScope {
+	graph := newCatalogGraph(catalog)
+	scope := &ComplianceScope{Team: team}
+
+	required := make(map[int][]ProjectDependency)
+	for _, dep := range catalog.ProjectDependencies {
+		if dep.DeletedAt == nil && !dep.Optional {
+			required[dep.DependentProjectID] = append(required[dep.DependentProjectID], dep)
+		}
+	}
+	for _, deps := range required {
+		sort.Slice(deps, func(i, j int) bool { return deps[i].ProvidingProjectID < deps[j].ProvidingProjectID })
+	}
+
+	for _, id := range graph.ids {
+		project := graph.projects[id]
+		if team != "" && !strings.EqualFold(project.ProjectStakeholderOwner, team) && !strings.EqualFold(project.ProjectStakeholderOncall, team) {
+			continue
+		}
+		if !inComplianceScope(project) {
+			if !project.InScopeForSOC2.Known() {
+				scope.UnknownSOC2++
+			}
+			continue
+		}
+
+		entry := ComplianceProject{
+			Project:       project,
+			MissingOwner:  strings.TrimSpace(project.ProjectStakeholderOwner) == "",
+			MissingOncall: strings.TrimSpace(project.ProjectStakeholderOncall) == "",
+		}
+		for _, dep := range required[project.ID] {
+			provider, ok := graph.projects[dep.ProvidingProjectID]
+			switch {
+			case !ok:
+				entry.OutOfScopeProviders = append(entry.OutOfScopeProviders, ComplianceProvider{Dependency: dep})
+			case !inComplianceScope(provider):
+				entry.OutOfScopeProviders = append(entry.OutOfScopeProviders, ComplianceProvider{Dependency: dep, Provider: &provider})
+			}
+		}
+		scope.Projects = append(scope.Projects, entry)
+	}
+
+	sort.SliceStable(scope.Projects, func(i, j int) bool {
+		ti, tj := effectiveCriticalityTier(scope.Projects[i].Project), effectiveCriticalityTier(scope.Projects[j].Project)
+		if ti.Less(tj) || tj.Less(ti) {
+			return ti.Less(tj)
+		}
+		return scope.Projects[i].Project.Permalink < scope.Projects[j].Project.Permalink
+	})
+	return scope
+}
+
+// complianceReasons returns why a project is in compliance scope
+func complianceReasons(project Project) string {
+	var reasons []string
+	if project.InScopeForSOC2.Yes() {
+		reasons = append(reasons, "SOC2")
+	}
+	if project.RequiredForCompliance {
+		reasons = append(reasons, "required for compliance")
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// complianceIssues summarizes the problems of an in-scope project for the overview table
+func complianceIssues(entry ComplianceProject) string {
+	var issues []string
+	if entry.MissingOwner {
+		issues = append(issues, "missing owner")
+	}
+	if entry.MissingOncall {
+		issues = append(issues, "missing on-call")
+	}
+	if n := len(entry.OutOfScopeProviders); n == 1 {
+		issues = append(issues, "1 out-of-scope provider")
+	} else if n > 1 {
+		issues = append(issues, fmt.Sprintf("%d out-of-scope providers", n))
+	}
+	return valueOrDash(strings.Join(issues, ", "))
+}
+
+// formatComplianceScope formats a compliance scope report for display
+func formatComplianceScope(scope *ComplianceScope) string {
+	result := "# Compliance Scope\n\n"
+	if scope.Team != "" {
+		result += fmt.Sprintf("**Scope:** team %s\n\n", scope.Team)
+	} else {
+		result += "**Scope:** all projects\n\n"
+	}
+
+	var flagged, outOfScope, missingOwner, missingOncall int
+	for _, entry := range scope.Projects {
+		if entry.flagged() {
+			flagged++
+		}
+		if len(entry.OutOfScopeProviders) > 0 {
+			outOfScope++
+		}
+		if entry.MissingOwner {
+			missingOwner++
+		}
+		if entry.MissingOncall {
+			missingOncall++
+		}
+	}
+
+	result += fmt.Sprintf("%d projects are in SOC2 scope or required for compliance; problems found in %d.", len(scope.Projects), flagged)
+	if scope.UnknownSOC2 > 0 {
+		result += fmt.Sprintf(" Projects with an unknown SOC2 scope, not listed: %d.", scope.UnknownSOC2)
+	}
+	result += "\n"
+
+	result += "\n## Summary\n"
+	result += fmt.Sprintf("- **Depend on Out-of-Scope Providers:** %d\n", outOfScope)
+	result += fmt.Sprintf("- **Missing Owner:** %d\n", missingOwner)
+	result += fmt.Sprintf("- **Missing On-Call:** %d\n", missingOncall)
+
+	result += fmt.Sprintf("\n## In-Scope Projects (%d)\n\n", len(scope.Projects))
+	if len(scope.Projects) == 0 {
+		return result + "None.\n"
+	}
+	result += "| Project | Tier | Reason | Owner | On-Call | Issues |\n"
+	result += "| --- | --- | --- | --- | --- | --- |\n"
+	for _, entry := range scope.Projects {
+		p := entry.Project
+		result += fmt.Sprintf("| %s (`%s`) | %s | %s | %s | %s | %s |\n",
+			tableCell(p.Name), p.Permalink, effectiveCriticalityTier(p), complianceReasons(p),
+			tableCell(valueOrDash(p.ProjectStakeholderOwner)), tableCell(valueOrDash(p.ProjectStakeholderOncall)),
+			complianceIssues(entry))
+	}
+
+	if outOfScope == 0 {
+		return result
+	}
+	result += fmt.Sprintf("\n## Required Dependencies on Out-of-Scope Providers (%d projects)\n", outOfScope)
+	for _, entry := range scope.Projects {
+		if len(entry.OutOfScopeProviders) == 0 {
+			continue
+		}
+		result += fmt.Sprintf("\n### %s (`%s`)\n", entry.Project.Name, entry.Project.Permalink)
+		for _, op := range entry.OutOfScopeProviders {
+			if op.Provider == nil {
+				result += fmt.Sprintf("- Project ID %d (deleted or not in the catalog)", op.Dependency.ProvidingProjectID)
+			} else {
+				result += fmt.Sprintf("- `%s` (%s, %s, owner: %s, SOC2: %s)", op.Provider.Permalink, op.Provider.Name,
+					effectiveCriticalityTier(*op.Provider), valueOrNone(op.Provider.ProjectStakeholderOwner), op.Provider.InScopeForSOC2)
+			}
+			if op.Dependency.Description != "" {
+				result += ": " + op.Dependency.Description
+			}
+			result += "\n"
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+// complianceSnapshot returns a catalog with SOC2 and compliance-required projects that
+// depend on in-scope, out-of-scope, optional, deleted and missing providers
+func complianceSnapshot() *Snapshot {
+	checkout := testProject(1, "checkout", "Team Payments", "Tier 1")
+	checkout.ProjectStakeholderOncall = "Payments On-Call"
+	checkout.InScopeForSOC2 = "Yes"
+
+	ledger := testProject(2, "ledger", "", "Tier 0")
+	ledger.InScopeForSOC2 = "no"
+	ledger.RequiredForCompliance = true
+
+	search := testProject(3, "search", "Team Discovery", "Tier 2")
+	search.InScopeForSOC2 = "No"
+
+	recommendations := testProject(4, "recommendations", "Team Discovery", "Tier 3")
+	recommendations.InScopeForSOC2 = "Unknown"
+
+	retired := testProject(5, "old-billing", "Team Payments", "Tier 1")
+	retired.InScopeForSOC2 = "Yes"
+	retired.DeletedAt = deletedAt()
+
+	optional := testDependency(3, 1, 4)
+	optional.Optional = true
+	toSearch := testDependency(2, 1, 3)
+	toSearch.Description = "Product lookups"
+
+	return &Snapshot{
+		Projects: []Project{checkout, ledger, search, recommendations, retired},
+		ProjectDependencies: []ProjectDependency{
+			testDependency(1, 1, 2),
+			toSearch,
+			optional,
+			testDependency(4, 2, 5),
+			testDependency(5, 2, 99),
+		},
+	}
+}
+
+func TestGetComplianceScope(t *testing.T) {
+	service := NewProjectService(NewSnapshotSource(complianceSnapshot()), NewValidator())
+
+	result, err := service.GetComplianceScope(context.Background(), "")
+	if err != nil {
+		t.Fatalf("GetComplianceScope failed: %v", err)
+	}
+	scope := result.Scope
+	if len(scope.Projects) != 2 || scope.Projects[0].Project.Permalink != "ledger" || scope.Projects[1].Project.Permalink != "checkout" {
+		t.Fatalf("expected ledger then checkout in scope, got %+v", scope.Projects)
+	}
+	if scope.UnknownSOC2 != 1 {
+		t.Errorf("UnknownSOC2 = %d, want 1", scope.UnknownSOC2)
+	}
+
+	ledger, checkout := scope.Projects[0], scope.Projects[1]
+	if !ledger.MissingOwner || !ledger.MissingOncall || len(ledger.OutOfScopeProviders) != 2 {
+		t.Errorf("ledger should miss its owner and on-call and depend on a deleted and a missing provider, got %+v", ledger)
+	}
+	for _, op := range ledger.OutOfScopeProviders {
+		if op.Provider != nil {
+			t.Errorf("expected no provider for the deleted or missing project %d, got %+v", op.Dependency.ProvidingProjectID, op.Provider)
+		}
+	}
+	if checkout.MissingOwner || checkout.MissingOncall || len(checkout.OutOfScopeProviders) != 1 || checkout.OutOfScopeProviders[0].Provider.Permalink != "search" {
+		t.Errorf("checkout should only depend on search out of scope, got %+v", checkout)
+	}
+	assertGolden(t, "compliance_scope", result.FormattedText)
+
+	filtered, err := service.GetComplianceScope(context.Background(), "payments on-call")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered.Scope.Projects) != 1 || filtered.Scope.Projects[0].Project.Permalink != "checkout" {
+		t.Errorf("expected only checkout for the payments on-call team, got %+v", filtered.Scope.Projects)
+	}
+}
+
+func TestComplianceScopeExamplePayloads(t *testing.T) {
+	snapshot, err := LoadSnapshot(filepath.Join("examples", "cerebro-api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scope := complianceScope(snapshot, "")
+
+	// Only example-service is in SOC2 scope, and its providers are not in the projects payload
+	if len(scope.Projects) != 1 || len(scope.Projects[0].OutOfScopeProviders) != 82 {
+		t.Fatalf("expected one in-scope project with its 82 required providers flagged, got %d projects", len(scope.Projects))
+	}
+	for _, entry := range scope.Projects {
+		if !inComplianceScope(entry.Project) {
+			t.Errorf("%s is listed but not in scope", entry.Project.Permalink)
+		}
+		for _, op := range entry.OutOfScopeProviders {
+			if op.Dependency.Optional || (op.Provider != nil && inComplianceScope(*op.Provider)) {
+				t.Errorf("%s: unexpected out-of-scope provider %+v", entry.Project.Permalink, op)
+			}
+		}
+	}
+}
+
+func TestComplianceScopeNormalizedValues(t *testing.T) {
+	gateway := testProject(1, "gateway", "  ", "Tier 1")
+	gateway.ProjectStakeholderOncall = "Edge On-Call"
+	gateway.InScopeForSOC2 = " YES "
+	vault := testProject(2, "vault", "Team Security", "Tier 1")
+	vault.ProjectStakeholderOncall = "Security On-Call"
+	vault.InScopeForSOC2 = "true"
+	metrics := testProject(3, "metrics", "Team Observability", "Tier 3")
+	metrics.InScopeForSOC2 = "No"
+
+	removed := testDependency(2, 1, 3)
+	removed.DeletedAt = deletedAt()
+	snapshot := &Snapshot{
+		Projects: []Project{gateway, vault, metrics},
+		ProjectDependencies: []ProjectDependency{
+			// In-scope providers and soft-deleted dependencies are not flagged
+			testDependency(1, 1, 2),
+			removed,
+		},
+	}
+	scope := complianceScope(snapshot, "")
+
+	if len(scope.Projects) != 2 || scope.UnknownSOC2 != 0 {
+		t.Fatalf("expected gateway and vault in scope, got %+v", scope.Projects)
+	}
+	gatewayEntry := scope.Projects[0]
+	if gatewayEntry.Project.Permalink != "gateway" || !gatewayEntry.MissingOwner || gatewayEntry.MissingOncall || len(gatewayEntry.OutOfScopeProviders) != 0 {
+		t.Errorf("gateway should only miss its blank owner, got %+v", gatewayEntry)
+	}
+	if scope.Projects[1].flagged() {
+		t.Errorf("vault should not be flagged, got %+v", scope.Projects[1])
+	}
+}
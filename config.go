@@ -1,36 +1,553 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Default configuration values
+const (
+	DefaultCerebroAPIBaseURL = "https://cerebro.zende.sk/projects.json"
+	DefaultHTTPTimeout       = 30 * time.Second
+	DefaultServerPort        = ":8080"
+	DefaultMCPEndpoint       = "/mcp"
+	DefaultMaxConcurrency    = 10
+	DefaultCacheTTL          = 5 * time.Minute
+	DefaultCatalogTTL        = 10 * time.Minute
+	DefaultTransport         = TransportStdio
+	DefaultMaxResponseChars  = 40000
+)
+
+// Supported server transports
+const (
+	TransportStdio = "stdio"
+	TransportHTTP  = "http"
+	TransportSSE   = "sse"
 )
 
 // Config holds application configuration
 type Config struct {
-	CerebroAPIBaseURL string
-	HTTPTimeout       time.Duration
-	ServerPort        string
-	MCPEndpoint       string
-	CerebroToken      string
-	HTTPMode          bool
-}
-
-// LoadConfig loads configuration from environment variables
-func LoadConfig() (*Config, error) {
-	cerebroToken := os.Getenv("CEREBRO_TOKEN")
-	if cerebroToken == "" {
-		return nil, fmt.Errorf("CEREBRO_TOKEN environment variable is required")
-	}
-
-	return &Config{
-		CerebroAPIBaseURL: "https://cerebro.zende.sk/projects.json",
-		HTTPTimeout:       30 * time.Second,
-		ServerPort:        getEnvOrDefault("SERVER_PORT", ":8080"),
-		MCPEndpoint:       getEnvOrDefault("MCP_ENDPOINT", "/mcp"),
-		CerebroToken:      cerebroToken,
-		HTTPMode:          os.Getenv("HTTP_MODE") == "true",
-	}, nil
+	CerebroAPIBaseURL   string
+	HTTPTimeout         time.Duration
+	ServerPort          string
+	MCPEndpoint         string
+	CerebroToken        string
+	CerebroTokenFile    string
+	CerebroTokenCommand string
+	Transport           string
+	MaxConcurrency      int
+	CacheEnabled        bool
+	CacheTTL            time.Duration
+	CatalogTTL          time.Duration
+	EnabledTools        []string
+	SnapshotPath        string
+	SnapshotDir         string
+	MaxResponseChars    int
+}
+
+// fileConfig is the on-disk YAML/JSON configuration format; nil fields are left unchanged
+type fileConfig struct {
+	Cerebro struct {
+		BaseURL        *string `yaml:"base_url" json:"base_url"`
+		Timeout        *string `yaml:"timeout" json:"timeout"`
+		MaxConcurrency *int    `yaml:"max_concurrency" json:"max_concurrency"`
+		TokenFile      *string `yaml:"token_file" json:"token_file"`
+		TokenCommand   *string `yaml:"token_command" json:"token_command"`
+	} `yaml:"cerebro" json:"cerebro"`
+	Cache struct {
+		Enabled    *bool   `yaml:"enabled" json:"enabled"`
+		TTL        *string `yaml:"ttl" json:"ttl"`
+		CatalogTTL *string `yaml:"catalog_ttl" json:"catalog_ttl"`
+	} `yaml:"cache" json:"cache"`
+	Server struct {
+		Port      *string `yaml:"port" json:"port"`
+		Endpoint  *string `yaml:"endpoint" json:"endpoint"`
+		Transport *string `yaml:"transport" json:"transport"`
+	} `yaml:"server" json:"server"`
+	Tools struct {
+		Enabled []string `yaml:"enabled" json:"enabled"`
+	} `yaml:"tools" json:"tools"`
+	Snapshot struct {
+		Path *string `yaml:"path" json:"path"`
+		Dir  *string `yaml:"dir" json:"dir"`
+	} `yaml:"snapshot" json:"snapshot"`
+	Output struct {
+		MaxChars *int `yaml:"max_chars" json:"max_chars"`
+	} `yaml:"output" json:"output"`
+}
+
+// ConfigFlags holds command-line overrides for configuration values
+type ConfigFlags struct {
+	fs             *flag.FlagSet
+	ConfigPath     string
+	BaseURL        string
+	Timeout        time.Duration
+	MaxConcurrency int
+	TokenFile      string
+	TokenCommand   string
+	CacheEnabled   bool
+	CacheTTL       time.Duration
+	CatalogTTL     time.Duration
+	EnabledTools   string
+	ServerPort     string
+	MCPEndpoint    string
+	Transport      string
+	SnapshotPath   string
+	SnapshotDir    string
+	MaxChars       int
+}
+
+// BindConfigFlags registers the configuration flags on the given flag set
+func BindConfigFlags(fs *flag.FlagSet) *ConfigFlags {
+	f := &ConfigFlags{fs: fs}
+	fs.StringVar(&f.ConfigPath, "config", "", "path to a YAML or JSON config file (env: CEREBRO_CONFIG)")
+	fs.StringVar(&f.BaseURL, "base-url", "", "Cerebro projects API URL")
+	fs.DurationVar(&f.Timeout, "timeout", 0, "timeout for Cerebro API requests")
+	fs.IntVar(&f.MaxConcurrency, "max-concurrency", 0, "maximum concurrent Cerebro API requests per tool call")
+	fs.StringVar(&f.TokenFile, "token-file", "", "read the Cerebro token from this file, reloading it when it changes")
+	fs.StringVar(&f.TokenCommand, "token-command", "", "run this shell command to obtain the Cerebro token")
+	fs.BoolVar(&f.CacheEnabled, "cache", false, "cache Cerebro API responses in memory")
+	fs.DurationVar(&f.CacheTTL, "cache-ttl", 0, "how long cached Cerebro API responses stay valid")
+	fs.DurationVar(&f.CatalogTTL, "catalog-ttl", 0, "how long the catalog fetched by catalog-wide tools is reused, 0 to fetch it on every call")
+	fs.StringVar(&f.EnabledTools, "tools", "", "comma-separated list of tools to enable (default all)")
+	fs.StringVar(&f.ServerPort, "port", "", "HTTP listen address")
+	fs.StringVar(&f.MCPEndpoint, "endpoint", "", "HTTP endpoint path")
+	fs.StringVar(&f.Transport, "transport", "", "server transport: stdio, http or sse")
+	fs.StringVar(&f.SnapshotPath, "snapshot", "", "serve from a snapshot file or directory instead of the live Cerebro API")
+	fs.StringVar(&f.SnapshotDir, "snapshot-dir", "", "directory of snapshots that catalog_diff compares")
+	fs.IntVar(&f.MaxChars, "max-response-chars", 0, "character budget of a tool response, 0 for no limit")
+	return f
+}
+
+// isSet reports whether the named flag was given on the command line
+func (f *ConfigFlags) isSet(name string) bool {
+	set := false
+	f.fs.Visit(func(fl *flag.Flag) {
+		if fl.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// LoadConfig loads configuration from defaults, then a config file, then
+// environment variables, then command-line flags. Later layers win. flags may be nil.
+// Credentials are checked separately by RequireCredentials so that commands which
+// never call Cerebro can run without a token.
+func LoadConfig(flags *ConfigFlags) (*Config, error) {
+	config, problems := loadConfig(flags)
+	if len(problems) > 0 {
+		return nil, &ConfigError{Problems: problems}
+	}
+	return config, nil
+}
+
+// loadConfig builds the layered configuration and returns it with every problem found
+func loadConfig(flags *ConfigFlags) (*Config, []string) {
+	config := &Config{
+		CerebroAPIBaseURL: DefaultCerebroAPIBaseURL,
+		HTTPTimeout:       DefaultHTTPTimeout,
+		ServerPort:        DefaultServerPort,
+		MCPEndpoint:       DefaultMCPEndpoint,
+		MaxConcurrency:    DefaultMaxConcurrency,
+		CacheTTL:          DefaultCacheTTL,
+		CatalogTTL:        DefaultCatalogTTL,
+		Transport:         DefaultTransport,
+		EnabledTools:      availableToolNames(),
+		MaxResponseChars:  DefaultMaxResponseChars,
+	}
+
+	var problems []string
+
+	configPath := os.Getenv("CEREBRO_CONFIG")
+	if flags != nil && flags.ConfigPath != "" {
+		configPath = flags.ConfigPath
+	}
+	if configPath != "" {
+		problems = append(problems, config.applyFile(configPath)...)
+	}
+
+	problems = append(problems, config.applyEnv()...)
+
+	if flags != nil {
+		config.applyFlags(flags)
+	}
+
+	problems = append(problems, config.validate()...)
+	return config, problems
+}
+
+// RequireCredentials reports an error if no usable Cerebro token source is configured
+func (c *Config) RequireCredentials() error {
+	if problems := c.validateCredentials(); len(problems) > 0 {
+		return &ConfigError{Problems: problems}
+	}
+	return nil
+}
+
+// validateCredentials checks that exactly one Cerebro token source is configured.
+// Offline snapshot mode never calls Cerebro and needs no token.
+func (c *Config) validateCredentials() []string {
+	if c.SnapshotPath != "" {
+		return nil
+	}
+
+	configured := 0
+	for _, value := range []string{c.CerebroToken, c.CerebroTokenFile, c.CerebroTokenCommand} {
+		if value != "" {
+			configured++
+		}
+	}
+
+	switch {
+	case configured == 0:
+		return []string{"a Cerebro token is required: set CEREBRO_TOKEN, CEREBRO_TOKEN_FILE or CEREBRO_TOKEN_COMMAND"}
+	case configured > 1:
+		return []string{"only one of CEREBRO_TOKEN, CEREBRO_TOKEN_FILE and CEREBRO_TOKEN_COMMAND may be set (or of token_file and token_command in the config file, or of -token-file and -token-command)"}
+	}
+
+	if c.CerebroTokenFile != "" {
+		if _, err := os.Stat(c.CerebroTokenFile); err != nil {
+			return []string{fmt.Sprintf("token file: %v", err)}
+		}
+	}
+	return nil
+}
+
+// TokenSource returns the configured source of the Cerebro API token
+func (c *Config) TokenSource() TokenSource {
+	switch {
+	case c.CerebroTokenFile != "":
+		return NewFileTokenSource(c.CerebroTokenFile)
+	case c.CerebroTokenCommand != "":
+		return NewCommandTokenSource(c.CerebroTokenCommand)
+	default:
+		return StaticToken(c.CerebroToken)
+	}
+}
+
+// applyFile overlays values from a YAML or JSON config file
+func (c *Config) applyFile(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []string{fmt.Sprintf("config file: %v", err)}
+	}
+
+	var fc fileConfig
+	var problems []string
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		problems, err = decodeJSONConfig(data, &fc)
+	} else {
+		problems, err = decodeYAMLConfig(data, &fc)
+	}
+	if err != nil {
+		return []string{fmt.Sprintf("config file %s: %v", path, err)}
+	}
+	for i, problem := range problems {
+		problems[i] = fmt.Sprintf("config file %s: %s", path, problem)
+	}
+
+	if fc.Cerebro.BaseURL != nil {
+		c.CerebroAPIBaseURL = *fc.Cerebro.BaseURL
+	}
+	if fc.Cerebro.Timeout != nil {
+		problems = append(problems, parseDurationInto(&c.HTTPTimeout, "cerebro.timeout", *fc.Cerebro.Timeout)...)
+	}
+	if fc.Cerebro.MaxConcurrency != nil {
+		c.MaxConcurrency = *fc.Cerebro.MaxConcurrency
+	}
+	var tokenFile, tokenCommand string
+	if fc.Cerebro.TokenFile != nil {
+		tokenFile = *fc.Cerebro.TokenFile
+	}
+	if fc.Cerebro.TokenCommand != nil {
+		tokenCommand = *fc.Cerebro.TokenCommand
+	}
+	c.setTokenSources("", tokenFile, tokenCommand)
+	if fc.Cache.Enabled != nil {
+		c.CacheEnabled = *fc.Cache.Enabled
+	}
+	if fc.Cache.TTL != nil {
+		problems = append(problems, parseDurationInto(&c.CacheTTL, "cache.ttl", *fc.Cache.TTL)...)
+	}
+	if fc.Cache.CatalogTTL != nil {
+		problems = append(problems, parseDurationInto(&c.CatalogTTL, "cache.catalog_ttl", *fc.Cache.CatalogTTL)...)
+	}
+	if fc.Server.Port != nil {
+		c.ServerPort = *fc.Server.Port
+	}
+	if fc.Server.Endpoint != nil {
+		c.MCPEndpoint = *fc.Server.Endpoint
+	}
+	if fc.Server.Transport != nil {
+		c.Transport = *fc.Server.Transport
+	}
+	if fc.Tools.Enabled != nil {
+		c.EnabledTools = fc.Tools.Enabled
+	}
+	if fc.Snapshot.Path != nil {
+		c.SnapshotPath = *fc.Snapshot.Path
+	}
+	if fc.Snapshot.Dir != nil {
+		c.SnapshotDir = *fc.Snapshot.Dir
+	}
+	if fc.Output.MaxChars != nil {
+		c.MaxResponseChars = *fc.Output.MaxChars
+	}
+	return problems
+}
+
+// yamlUnknownField matches the error yaml.v3 reports for a key that fileConfig lacks
+var yamlUnknownField = regexp.MustCompile(`^(line \d+): field (.+) not found in type`)
+
+// decodeYAMLConfig decodes a YAML config file. Unknown keys and values of the wrong type
+// are returned as problems while the rest is still decoded; err means the file is not
+// valid YAML.
+func decodeYAMLConfig(data []byte, fc *fileConfig) (problems []string, err error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err = decoder.Decode(fc)
+	if err == nil || errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return nil, err
+	}
+	for _, message := range typeErr.Errors {
+		if m := yamlUnknownField.FindStringSubmatch(message); m != nil {
+			message = fmt.Sprintf("%s: unknown key %q", m[1], m[2])
+		}
+		problems = append(problems, message)
+	}
+	return problems, nil
+}
+
+// decodeJSONConfig decodes a JSON config file, returning an unknown key as a problem.
+// encoding/json stops at the first unknown key, so the file is then decoded again without
+// the check to keep the known settings.
+func decodeJSONConfig(data []byte, fc *fileConfig) (problems []string, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(fc)
+	if err == nil {
+		return nil, nil
+	}
+
+	field, unknown := strings.CutPrefix(err.Error(), "json: unknown field ")
+	if !unknown {
+		return nil, err
+	}
+	*fc = fileConfig{}
+	if err := json.Unmarshal(data, fc); err != nil {
+		return nil, err
+	}
+	return []string{"unknown key " + field}, nil
+}
+
+// applyEnv overlays values from environment variables
+func (c *Config) applyEnv() []string {
+	var problems []string
+
+	c.setTokenSources(os.Getenv("CEREBRO_TOKEN"), os.Getenv("CEREBRO_TOKEN_FILE"), os.Getenv("CEREBRO_TOKEN_COMMAND"))
+	c.CerebroAPIBaseURL = getEnvOrDefault("CEREBRO_API_BASE_URL", c.CerebroAPIBaseURL)
+	c.SnapshotPath = getEnvOrDefault("CEREBRO_SNAPSHOT", c.SnapshotPath)
+	c.SnapshotDir = getEnvOrDefault("CEREBRO_SNAPSHOT_DIR", c.SnapshotDir)
+	c.ServerPort = getEnvOrDefault("SERVER_PORT", c.ServerPort)
+	c.MCPEndpoint = getEnvOrDefault("MCP_ENDPOINT", c.MCPEndpoint)
+
+	c.Transport = getEnvOrDefault("MCP_TRANSPORT", c.Transport)
+	// HTTP_MODE=true is kept for existing deployments that predate MCP_TRANSPORT
+	if os.Getenv("MCP_TRANSPORT") == "" && os.Getenv("HTTP_MODE") == "true" {
+		c.Transport = TransportHTTP
+	}
+	if value := os.Getenv("CEREBRO_HTTP_TIMEOUT"); value != "" {
+		problems = append(problems, parseDurationInto(&c.HTTPTimeout, "CEREBRO_HTTP_TIMEOUT", value)...)
+	}
+	if value := os.Getenv("CEREBRO_MAX_CONCURRENCY"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("CEREBRO_MAX_CONCURRENCY: %q is not an integer", value))
+		} else {
+			c.MaxConcurrency = n
+		}
+	}
+	if value := os.Getenv("CEREBRO_CACHE_ENABLED"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("CEREBRO_CACHE_ENABLED: %q is not a boolean", value))
+		} else {
+			c.CacheEnabled = enabled
+		}
+	}
+	if value := os.Getenv("CEREBRO_CACHE_TTL"); value != "" {
+		problems = append(problems, parseDurationInto(&c.CacheTTL, "CEREBRO_CACHE_TTL", value)...)
+	}
+	if value := os.Getenv("CEREBRO_CATALOG_TTL"); value != "" {
+		problems = append(problems, parseDurationInto(&c.CatalogTTL, "CEREBRO_CATALOG_TTL", value)...)
+	}
+	if value := os.Getenv("CEREBRO_MAX_RESPONSE_CHARS"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("CEREBRO_MAX_RESPONSE_CHARS: %q is not an integer", value))
+		} else {
+			c.MaxResponseChars = n
+		}
+	}
+	if value := os.Getenv("CEREBRO_ENABLED_TOOLS"); value != "" {
+		c.EnabledTools = splitList(value)
+	}
+
+	return problems
+}
+
+// setTokenSources replaces every token source of the lower layers when a layer sets any
+// of them, so that e.g. CEREBRO_TOKEN overrides cerebro.token_file from the config file.
+// Sources set together in the same layer are left for validateCredentials to reject.
+func (c *Config) setTokenSources(token, file, command string) {
+	if token == "" && file == "" && command == "" {
+		return
+	}
+	c.CerebroToken, c.CerebroTokenFile, c.CerebroTokenCommand = token, file, command
+}
+
+// applyFlags overlays values from command-line flags that were explicitly set
+func (c *Config) applyFlags(f *ConfigFlags) {
+	if f.isSet("base-url") {
+		c.CerebroAPIBaseURL = f.BaseURL
+	}
+	if f.isSet("timeout") {
+		c.HTTPTimeout = f.Timeout
+	}
+	if f.isSet("max-concurrency") {
+		c.MaxConcurrency = f.MaxConcurrency
+	}
+	if f.isSet("token-file") || f.isSet("token-command") {
+		c.setTokenSources("", f.TokenFile, f.TokenCommand)
+	}
+	if f.isSet("cache") {
+		c.CacheEnabled = f.CacheEnabled
+	}
+	if f.isSet("cache-ttl") {
+		c.CacheTTL = f.CacheTTL
+	}
+	if f.isSet("catalog-ttl") {
+		c.CatalogTTL = f.CatalogTTL
+	}
+	if f.isSet("tools") {
+		c.EnabledTools = splitList(f.EnabledTools)
+	}
+	if f.isSet("port") {
+		c.ServerPort = f.ServerPort
+	}
+	if f.isSet("endpoint") {
+		c.MCPEndpoint = f.MCPEndpoint
+	}
+	if f.isSet("transport") {
+		c.Transport = f.Transport
+	}
+	if f.isSet("snapshot") {
+		c.SnapshotPath = f.SnapshotPath
+	}
+	if f.isSet("snapshot-dir") {
+		c.SnapshotDir = f.SnapshotDir
+	}
+	if f.isSet("max-response-chars") {
+		c.MaxResponseChars = f.MaxChars
+	}
+}
+
+// validate checks the final configuration and returns every problem found
+func (c *Config) validate() []string {
+	var problems []string
+
+	if !strings.HasPrefix(c.CerebroAPIBaseURL, "http://") && !strings.HasPrefix(c.CerebroAPIBaseURL, "https://") {
+		problems = append(problems, fmt.Sprintf("Cerebro base URL %q must start with http:// or https://", c.CerebroAPIBaseURL))
+	}
+	if c.HTTPTimeout <= 0 {
+		problems = append(problems, "Cerebro timeout must be greater than zero")
+	}
+	if c.MaxConcurrency < 1 {
+		problems = append(problems, "max concurrency must be at least 1")
+	}
+	if c.MaxResponseChars < 0 {
+		problems = append(problems, "max response chars must not be negative")
+	}
+	if c.CacheEnabled && c.CacheTTL <= 0 {
+		problems = append(problems, "cache TTL must be greater than zero when the cache is enabled")
+	}
+	if c.CatalogTTL < 0 {
+		problems = append(problems, "catalog TTL must not be negative")
+	}
+	if !strings.HasPrefix(c.MCPEndpoint, "/") {
+		problems = append(problems, fmt.Sprintf("MCP endpoint %q must start with /", c.MCPEndpoint))
+	}
+	switch c.Transport {
+	case TransportStdio, TransportHTTP, TransportSSE:
+	default:
+		problems = append(problems, fmt.Sprintf("transport %q must be one of stdio, http or sse", c.Transport))
+	}
+	if c.SnapshotPath != "" {
+		if _, err := os.Stat(c.SnapshotPath); err != nil {
+			problems = append(problems, fmt.Sprintf("snapshot: %v", err))
+		}
+	}
+	if c.SnapshotDir != "" {
+		if info, err := os.Stat(c.SnapshotDir); err != nil {
+			problems = append(problems, fmt.Sprintf("snapshot directory: %v", err))
+		} else if !info.IsDir() {
+			problems = append(problems, fmt.Sprintf("snapshot directory %s is not a directory", c.SnapshotDir))
+		}
+	}
+	if len(c.EnabledTools) == 0 {
+		problems = append(problems, "at least one tool must be enabled")
+	}
+
+	known := make(map[string]bool)
+	for _, name := range availableToolNames() {
+		known[name] = true
+	}
+	for _, name := range c.EnabledTools {
+		if !known[name] {
+			problems = append(problems, fmt.Sprintf("unknown tool %q (available: %s)", name, strings.Join(availableToolNames(), ", ")))
+		}
+	}
+
+	return problems
+}
+
+// parseDurationInto parses value into target, returning a problem description on failure
+func parseDurationInto(target *time.Duration, name, value string) []string {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %q is not a valid duration (e.g. 30s, 5m)", name, value)}
+	}
+	*target = d
+	return nil
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
 
 func getEnvOrDefault(key, defaultValue string) string {
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// configEnvVars are the environment variables loadConfig reads
+var configEnvVars = []string{
+	"CEREBRO_CONFIG", "CEREBRO_TOKEN", "CEREBRO_TOKEN_FILE", "CEREBRO_TOKEN_COMMAND",
+	"CEREBRO_API_BASE_URL", "CEREBRO_SNAPSHOT", "CEREBRO_SNAPSHOT_DIR", "SERVER_PORT",
+	"MCP_ENDPOINT", "MCP_TRANSPORT", "HTTP_MODE", "CEREBRO_HTTP_TIMEOUT",
+	"CEREBRO_MAX_CONCURRENCY", "CEREBRO_CACHE_ENABLED", "CEREBRO_CACHE_TTL", "CEREBRO_CATALOG_TTL",
+	"CEREBRO_MAX_RESPONSE_CHARS", "CEREBRO_ENABLED_TOOLS",
+}
+
+// loadTestConfig loads the configuration from a config file with the given name and
+// content (none if name is empty), the given environment and command-line arguments
+func loadTestConfig(t *testing.T, name, content string, env map[string]string, args ...string) (*Config, []string) {
+	t.Helper()
+
+	for _, key := range configEnvVars {
+		t.Setenv(key, "")
+	}
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := BindConfigFlags(fs)
+	if name != "" {
+		path := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		args = append([]string{"-config", path}, args...)
+	}
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+	return loadConfig(flags)
+}
+
+func TestLoadConfigTokenSourcePrecedence(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		file        string
+		env         map[string]string
+		args        []string
+		wantToken   string
+		wantFile    string
+		wantCommand string
+		wantProblem string
+	}{
+		{
+			name:      "environment token overrides the config file token file",
+			file:      "cerebro:\n  token_file: " + tokenFile + "\n",
+			env:       map[string]string{"CEREBRO_TOKEN": "env-token"},
+			wantToken: "env-token",
+		},
+		{
+			name:        "environment command overrides the config file token file",
+			file:        "cerebro:\n  token_file: " + tokenFile + "\n",
+			env:         map[string]string{"CEREBRO_TOKEN_COMMAND": "echo env"},
+			wantCommand: "echo env",
+		},
+		{
+			name:     "flag token file overrides the environment token",
+			env:      map[string]string{"CEREBRO_TOKEN": "env-token"},
+			args:     []string{"-token-file", tokenFile},
+			wantFile: tokenFile,
+		},
+		{
+			name:        "flag command overrides the config file and the environment",
+			file:        "cerebro:\n  token_file: " + tokenFile + "\n",
+			env:         map[string]string{"CEREBRO_TOKEN_FILE": tokenFile},
+			args:        []string{"-token-command", "echo flag"},
+			wantCommand: "echo flag",
+		},
+		{
+			name:     "config file token file is kept without overrides",
+			file:     "cerebro:\n  token_file: " + tokenFile + "\n",
+			wantFile: tokenFile,
+		},
+		{
+			name:        "two sources in the same layer conflict",
+			env:         map[string]string{"CEREBRO_TOKEN": "env-token", "CEREBRO_TOKEN_COMMAND": "echo env"},
+			wantToken:   "env-token",
+			wantCommand: "echo env",
+			wantProblem: "only one of CEREBRO_TOKEN",
+		},
+		{
+			name:        "no source",
+			wantProblem: "a Cerebro token is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := ""
+			if tt.file != "" {
+				name = "config.yaml"
+			}
+			config, problems := loadTestConfig(t, name, tt.file, tt.env, tt.args...)
+			if len(problems) > 0 {
+				t.Fatalf("unexpected problems: %v", problems)
+			}
+			if config.CerebroToken != tt.wantToken || config.CerebroTokenFile != tt.wantFile || config.CerebroTokenCommand != tt.wantCommand {
+				t.Errorf("token sources = %q, %q, %q; want %q, %q, %q", config.CerebroToken, config.CerebroTokenFile, config.CerebroTokenCommand,
+					tt.wantToken, tt.wantFile, tt.wantCommand)
+			}
+
+			err := config.RequireCredentials()
+			switch {
+			case tt.wantProblem == "" && err != nil:
+				t.Errorf("RequireCredentials failed: %v", err)
+			case tt.wantProblem != "" && (err == nil || !strings.Contains(err.Error(), tt.wantProblem)):
+				t.Errorf("RequireCredentials = %v, want a problem containing %q", err, tt.wantProblem)
+			}
+		})
+	}
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	file := "cerebro:\n  base_url: https://file.example.com\n  timeout: 10s\n  max_concurrency: 4\n"
+
+	tests := []struct {
+		name            string
+		file            string
+		env             map[string]string
+		args            []string
+		wantBaseURL     string
+		wantTimeout     time.Duration
+		wantConcurrency int
+	}{
+		{
+			name:            "defaults",
+			wantBaseURL:     DefaultCerebroAPIBaseURL,
+			wantTimeout:     DefaultHTTPTimeout,
+			wantConcurrency: DefaultMaxConcurrency,
+		},
+		{
+			name:            "config file overrides the defaults",
+			file:            file,
+			wantBaseURL:     "https://file.example.com",
+			wantTimeout:     10 * time.Second,
+			wantConcurrency: 4,
+		},
+		{
+			name:            "environment overrides the config file",
+			file:            file,
+			env:             map[string]string{"CEREBRO_API_BASE_URL": "https://env.example.com", "CEREBRO_HTTP_TIMEOUT": "20s"},
+			wantBaseURL:     "https://env.example.com",
+			wantTimeout:     20 * time.Second,
+			wantConcurrency: 4,
+		},
+		{
+			name:            "flags override the environment",
+			file:            file,
+			env:             map[string]string{"CEREBRO_API_BASE_URL": "https://env.example.com", "CEREBRO_MAX_CONCURRENCY": "6"},
+			args:            []string{"-base-url", "https://flag.example.com", "-max-concurrency", "8"},
+			wantBaseURL:     "https://flag.example.com",
+			wantTimeout:     10 * time.Second,
+			wantConcurrency: 8,
+		},
+		{
+			name:            "flags left at their defaults do not override",
+			env:             map[string]string{"CEREBRO_MAX_CONCURRENCY": "6"},
+			args:            []string{"-base-url", "https://flag.example.com"},
+			wantBaseURL:     "https://flag.example.com",
+			wantTimeout:     DefaultHTTPTimeout,
+			wantConcurrency: 6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := ""
+			if tt.file != "" {
+				name = "config.yaml"
+			}
+			config, problems := loadTestConfig(t, name, tt.file, tt.env, tt.args...)
+			if len(problems) > 0 {
+				t.Fatalf("unexpected problems: %v", problems)
+			}
+			if config.CerebroAPIBaseURL != tt.wantBaseURL {
+				t.Errorf("base URL = %q, want %q", config.CerebroAPIBaseURL, tt.wantBaseURL)
+			}
+			if config.HTTPTimeout != tt.wantTimeout {
+				t.Errorf("timeout = %v, want %v", config.HTTPTimeout, tt.wantTimeout)
+			}
+			if config.MaxConcurrency != tt.wantConcurrency {
+				t.Errorf("max concurrency = %d, want %d", config.MaxConcurrency, tt.wantConcurrency)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFileFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "config.yaml",
+			content: `cerebro:
+  base_url: https://cerebro.example.com
+  timeout: 15s
+cache:
+  enabled: true
+  ttl: 1m
+server:
+  transport: http
+tools:
+  enabled: [project_get_details, project_get_dependencies]
+output:
+  max_chars: 1000
+`,
+		},
+		{
+			name: "config.json",
+			content: `{
+  "cerebro": {"base_url": "https://cerebro.example.com", "timeout": "15s"},
+  "cache": {"enabled": true, "ttl": "1m"},
+  "server": {"transport": "http"},
+  "tools": {"enabled": ["project_get_details", "project_get_dependencies"]},
+  "output": {"max_chars": 1000}
+}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, problems := loadTestConfig(t, tt.name, tt.content, nil)
+			if len(problems) > 0 {
+				t.Fatalf("unexpected problems: %v", problems)
+			}
+			if config.CerebroAPIBaseURL != "https://cerebro.example.com" || config.HTTPTimeout != 15*time.Second {
+				t.Errorf("cerebro = %q, %v", config.CerebroAPIBaseURL, config.HTTPTimeout)
+			}
+			if !config.CacheEnabled || config.CacheTTL != time.Minute {
+				t.Errorf("cache = %t, %v", config.CacheEnabled, config.CacheTTL)
+			}
+			if config.Transport != TransportHTTP || config.MaxResponseChars != 1000 {
+				t.Errorf("transport = %q, max chars = %d", config.Transport, config.MaxResponseChars)
+			}
+			if got := strings.Join(config.EnabledTools, ","); got != "project_get_details,project_get_dependencies" {
+				t.Errorf("enabled tools = %s", got)
+			}
+		})
+	}
+
+	// A JSON file is not parsed as YAML and the other way around
+	if _, problems := loadTestConfig(t, "config.json", "cerebro:\n  timeout: 15s\n", nil); len(problems) != 1 || !strings.Contains(problems[0], "config.json") {
+		t.Errorf("expected a YAML config.json to fail to parse, got %v", problems)
+	}
+}
+
+func TestLoadConfigReportsEveryProblem(t *testing.T) {
+	file := "cerebro:\n  base_url: cerebro.example.com\n  timeout: soon\n  tiemout: 5s\ncache:\n  enabld: true\n"
+	env := map[string]string{
+		"CEREBRO_MAX_CONCURRENCY": "0",
+		"CEREBRO_CACHE_TTL":       "later",
+		"MCP_TRANSPORT":           "carrier-pigeon",
+	}
+
+	_, problems := loadTestConfig(t, "config.yaml", file, env, "-tools", "project_get_details,project_teleport")
+
+	want := []string{
+		`cerebro.timeout: "soon" is not a valid duration`,
+		`line 4: unknown key "tiemout"`,
+		`line 6: unknown key "enabld"`,
+		`CEREBRO_CACHE_TTL: "later" is not a valid duration`,
+		`Cerebro base URL "cerebro.example.com" must start with http:// or https://`,
+		"max concurrency must be at least 1",
+		`transport "carrier-pigeon" must be one of stdio, http or sse`,
+		`unknown tool "project_teleport"`,
+	}
+	if len(problems) != len(want) {
+		t.Errorf("got %d problems, want %d: %v", len(problems), len(want), problems)
+	}
+	for _, w := range want {
+		found := false
+		for _, problem := range problems {
+			if strings.Contains(problem, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("missing problem %q in %v", w, problems)
+		}
+	}
+
+	// An unknown key in a JSON file is reported and the known settings still apply
+	config, problems := loadTestConfig(t, "config.json", `{"cerebro": {"timeout": "15s", "tiemout": "5s"}}`, nil)
+	if len(problems) != 1 || !strings.Contains(problems[0], `unknown key "tiemout"`) {
+		t.Errorf("expected the unknown JSON key to be reported, got %v", problems)
+	}
+	if config.HTTPTimeout != 15*time.Second {
+		t.Errorf("timeout = %v, want the known setting to apply", config.HTTPTimeout)
+	}
+
+	// LoadConfig reports them together as one error
+	for _, key := range configEnvVars {
+		t.Setenv(key, "")
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := BindConfigFlags(fs)
+	if err := fs.Parse([]string{"-transport", "carrier-pigeon", "-max-concurrency", "0"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadConfig(flags)
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || len(configErr.Problems) != 2 {
+		t.Errorf("expected a ConfigError with 2 problems, got %v", err)
+	}
+}
+
+func TestExampleConfig(t *testing.T) {
+	content, err := os.ReadFile("config.example.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, problems := loadTestConfig(t, "config.yaml", string(content), map[string]string{"CEREBRO_TOKEN": "example"})
+	if len(problems) != 0 {
+		t.Fatalf("the example configuration has problems: %v", problems)
+	}
+
+	// The example lists every tool except catalog_diff, which needs the commented-out
+	// snapshot directory
+	var want []string
+	for _, name := range availableToolNames() {
+		if name != ToolCatalogDiff {
+			want = append(want, name)
+		}
+	}
+	if got := strings.Join(config.EnabledTools, ","); got != strings.Join(want, ",") {
+		t.Errorf("enabled tools = %s, want %s", got, strings.Join(want, ","))
+	}
+}
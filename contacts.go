@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// IncidentContact is a row of an incident contact sheet. Project is nil for providers that
+// do not exist in Cerebro or could not be fetched, in which case Error says why.
+type IncidentContact struct {
+	ProjectID  int
+	Project    *Project
+	Dependency *ProjectDependency
+	Error      string
+}
+
+// GetIncidentContacts compiles the contacts of a project and of the providers of its
+// direct required dependencies
+func (s *ProjectService) GetIncidentContacts(ctx context.Context, permalink string) (*IncidentContactsResult, error) {
+	if err := s.validator.ValidateProjectPermalink(permalink); err != nil {
+		return nil, err
+	}
+
+	found, dependencies, err := s.source.FindProject(ctx, permalink, true)
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, s.projectNotFound(permalink)
+	}
+	project := *found
+
+	var required []ProjectDependency
+	optional := 0
+	for _, dep := range s.filterDependencies(dependencies, project.ID) {
+		switch {
+		case dep.DeletedAt != nil:
+		case dep.Optional:
+			optional++
+		default:
+			required = append(required, dep)
+		}
+	}
+
+	contacts := []IncidentContact{{ProjectID: project.ID, Project: &project}}
+	for _, res := range s.fetchDependenciesAsync(ctx, required) {
+		contact := IncidentContact{ProjectID: res.dep.ProvidingProjectID, Project: res.providingProject, Dependency: &res.dep}
+		switch {
+		case res.err != nil:
+			contact.Error = "could not be fetched"
+		case res.providingProject == nil:
+			contact.Error = "not found in Cerebro"
+		}
+		contacts = append(contacts, contact)
+	}
+
+	return &IncidentContactsResult{
+		Project:       project,
+		Contacts:      contacts,
+		FormattedText: formatIncidentContacts(project, contacts, optional),
+	}, nil
+}
+
+// formatIncidentContacts formats an incident contact sheet as a markdown table
+func formatIncidentContacts(project Project, contacts []IncidentContact, optional int) string {
+	result := fmt.Sprintf("# Incident Contacts for: %s\n\n", project.Name)
+	result += fmt.Sprintf("The project and the providers of its %d direct required dependencies.", len(contacts)-1)
+	if optional > 0 {
+		result += fmt.Sprintf(" Optional dependencies not listed: %d.", optional)
+	}
+	result += "\n\n"
+
+	result += "| Project | Tier | Owner | On-Call | Slack | Alerts (prod / staging / dev) | Deployments |\n"
+	result += "| --- | --- | --- | --- | --- | --- | --- |\n"
+	for i, contact := range contacts {
+		if contact.Project == nil {
+			result += fmt.Sprintf("| Project ID %d (%s) | | | | | | |\n", contact.ProjectID, contact.Error)
+			continue
+		}
+
+		p := contact.Project
+		name := tableCell(p.Name)
+		if i == 0 {
+			name = "**" + name + "**"
+		}
+		name += fmt.Sprintf(" (`%s`)", p.Permalink)
+		alerts := fmt.Sprintf("%s / %s / %s", slackChannel(p.SlackChannelAlerts), slackChannel(p.SlackChannelAlertsStaging), slackChannel(p.SlackChannelAlertsDev))
+
+		result += fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n",
+			name, tableCell(effectiveCriticalityTier(*p).String()),
+			tableCell(valueOrDash(p.ProjectStakeholderOwner)), tableCell(valueOrDash(p.ProjectStakeholderOncall)),
+			slackChannel(p.SlackChannel), alerts, tableCell(valueOrDash(strings.Join(deploymentURLs(*p), ", "))))
+	}
+
+	return result
+}
+
+// slackChannel formats a Slack channel name with a leading #, or a dash when it is empty
+func slackChannel(name string) string {
+	if name == "" {
+		return "-"
+	}
+	return "#" + strings.TrimPrefix(name, "#")
+}
+
+// valueOrDash returns value, or a dash when it is empty
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
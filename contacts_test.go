@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGetIncidentContacts(t *testing.T) {
+	snapshot := graphSnapshot()
+	checkout := &snapshot.Projects[0]
+	checkout.ProjectStakeholderOncall = "Checkout On-Call"
+	checkout.SlackChannel = "checkout"
+	checkout.SlackChannelAlerts = "checkout-alerts"
+	checkout.SlackChannelAlertsStaging = "#checkout-alerts-staging"
+	checkout.PrimaryDeploymentUrl = "https://deploy.example.com/checkout"
+	service := NewProjectService(NewSnapshotSource(snapshot), NewValidator())
+
+	result, err := service.GetIncidentContacts(context.Background(), "checkout")
+	if err != nil {
+		t.Fatalf("GetIncidentContacts failed: %v", err)
+	}
+
+	// checkout requires billing; the optional fraud-check and deleted ledger dependencies are left out
+	if len(result.Contacts) != 2 || result.Contacts[1].Project.Permalink != "billing" {
+		t.Errorf("contacts = %+v, want checkout and billing", result.Contacts)
+	}
+	assertGolden(t, "incident_contacts", result.FormattedText)
+
+	billing, err := service.GetIncidentContacts(context.Background(), "billing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(billing.FormattedText, "| Project ID 99 (not found in Cerebro) |") {
+		t.Errorf("expected a row for the missing provider:\n%s", billing.FormattedText)
+	}
+}
+
+func TestIncidentContactsFromCerebro(t *testing.T) {
+	fake := newFakeCerebro(t)
+	fake.addProject(fakeProvider(8, "Auth Service"))
+	service := newTestService(fake)
+
+	result, err := service.GetIncidentContacts(context.Background(), "example-service")
+	if err != nil {
+		t.Fatalf("GetIncidentContacts failed: %v", err)
+	}
+	// 83 dependencies, one of them optional
+	if got := len(result.Contacts); got != 83 {
+		t.Errorf("contacts = %d, want the project and 82 required providers", got)
+	}
+	if !strings.Contains(result.FormattedText, "| Auth Service (`auth-service`) | Tier 1 | Team 8 |") {
+		t.Errorf("expected a row for Auth Service")
+	}
+}
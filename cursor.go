@@ -0,0 +1,81 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxListLimit caps the limit argument of list tools
+const MaxListLimit = 500
+
+// cursorPrefix versions the cursor format so that old cursors can be rejected cleanly
+const cursorPrefix = "v2:"
+
+// cursorQueryHash returns a short hash of the query a cursor belongs to
+func cursorQueryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:6])
+}
+
+// encodeCursor returns an opaque cursor that resumes the listing of query at offset. query
+// describes everything that selects and orders the listing, so that the cursor cannot be
+// reused with other options.
+func encodeCursor(query string, offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + cursorQueryHash(query) + ":" + strconv.Itoa(offset)))
+}
+
+// decodeCursor returns the offset of a cursor made by encodeCursor for the same query. An
+// empty cursor starts at the beginning.
+func decodeCursor(cursor, query string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(data), cursorPrefix) {
+		return 0, &ValidationError{Field: "cursor", Message: "is not a valid cursor"}
+	}
+	hash, offsetText, ok := strings.Cut(strings.TrimPrefix(string(data), cursorPrefix), ":")
+	if !ok {
+		return 0, &ValidationError{Field: "cursor", Message: "is not a valid cursor"}
+	}
+	offset, err := strconv.Atoi(offsetText)
+	if err != nil || offset < 0 {
+		return 0, &ValidationError{Field: "cursor", Message: "is not a valid cursor"}
+	}
+	if hash != cursorQueryHash(query) {
+		return 0, &ValidationError{Field: "cursor", Message: "belongs to a listing with other arguments; repeat the arguments of the call that returned it, or start again without a cursor"}
+	}
+	return offset, nil
+}
+
+// pageRange returns the bounds of the page of at most limit items that cursor selects
+// from the listing of query with total items. A limit of 0 selects every remaining item.
+func pageRange(cursor, query string, limit, total int) (start, end int, err error) {
+	start, err = cursorOffset(cursor, query, total)
+	if err != nil {
+		return 0, 0, err
+	}
+	end = total
+	if limit > 0 && start+limit < total {
+		end = start + limit
+	}
+	return start, end, nil
+}
+
+// cursorOffset decodes a cursor into an offset into the listing of query with total items.
+// A cursor always points at an item, so offsets at or past the end are rejected.
+func cursorOffset(cursor, query string, total int) (int, error) {
+	offset, err := decodeCursor(cursor, query)
+	if err != nil {
+		return 0, err
+	}
+	if offset > 0 && offset >= total {
+		return 0, &ValidationError{Field: "cursor", Message: fmt.Sprintf("is past the end of the %d results; the data may have changed, start again without a cursor", total)}
+	}
+	return offset, nil
+}
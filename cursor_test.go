@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+const testCursorQuery = "dependencies:9:false:all:none"
+
+func TestPageRange(t *testing.T) {
+	tests := []struct {
+		cursor             string
+		limit, total       int
+		wantStart, wantEnd int
+	}{
+		{"", 0, 83, 0, 83},
+		{"", 10, 83, 0, 10},
+		{encodeCursor(testCursorQuery, 80), 10, 83, 80, 83},
+		{encodeCursor(testCursorQuery, 82), 10, 83, 82, 83},
+		{"", 10, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		start, end, err := pageRange(tt.cursor, testCursorQuery, tt.limit, tt.total)
+		if err != nil || start != tt.wantStart || end != tt.wantEnd {
+			t.Errorf("pageRange(%q, %d, %d) = %d, %d, %v; want %d, %d", tt.cursor, tt.limit, tt.total, start, end, err, tt.wantStart, tt.wantEnd)
+		}
+	}
+}
+
+func TestCursorOffset(t *testing.T) {
+	for _, offset := range []int{0, 1, 25, 82} {
+		got, err := cursorOffset(encodeCursor(testCursorQuery, offset), testCursorQuery, 83)
+		if err != nil || got != offset {
+			t.Errorf("cursorOffset(encodeCursor(%d)) = %d, %v", offset, got, err)
+		}
+	}
+
+	if got, err := cursorOffset("", testCursorQuery, 10); err != nil || got != 0 {
+		t.Errorf("expected an empty cursor to start at 0, got %d, %v", got, err)
+	}
+	if got, err := cursorOffset("", testCursorQuery, 0); err != nil || got != 0 {
+		t.Errorf("expected an empty cursor to start an empty listing, got %d, %v", got, err)
+	}
+
+	invalid := []string{
+		"not a cursor!",
+		"djE6LTE", // v1:-1
+		"MTI",     // 12
+		"djE6MTI", // a v1 cursor without a query hash
+		encodeCursor(testCursorQuery, 10),
+		encodeCursor(testCursorQuery, 11),
+		encodeCursor("dependencies:9:false:optional:none", 1),
+	}
+	for _, cursor := range invalid {
+		_, err := cursorOffset(cursor, testCursorQuery, 10)
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "cursor" {
+			t.Errorf("cursorOffset(%q): expected a cursor ValidationError, got %v", cursor, err)
+		}
+	}
+	if _, err := cursorOffset(encodeCursor(testCursorQuery, 1), testCursorQuery, 0); err == nil {
+		t.Error("expected a cursor into an empty listing to be rejected")
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DependencyCycle is a strongly connected group of projects that depend on each other
+type DependencyCycle struct {
+	Projects []Project
+	// Path is an example cycle through the group, starting and ending at the same project
+	Path []int
+	// OptionalEdges are the optional dependencies inside the group; empty for hard cycles
+	OptionalEdges []ProjectDependency
+}
+
+// DependencyCycles is the result of cycle detection over the whole catalog
+type DependencyCycles struct {
+	ProjectCount    int
+	DependencyCount int
+	// Hard cycles are made only of required dependencies
+	Hard []DependencyCycle
+	// Soft cycles only exist through optional dependencies
+	Soft []DependencyCycle
+}
+
+// GetDependencyCycles loads the whole catalog and reports its dependency cycles
+func (s *ProjectService) GetDependencyCycles(ctx context.Context) (*DependencyCyclesResult, error) {
+	catalog, err := s.source.Catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := newCatalogGraph(catalog)
+	cycles := findDependencyCycles(graph)
+
+	return &DependencyCyclesResult{
+		Cycles:        cycles,
+		FormattedText: formatDependencyCycles(graph, cycles),
+	}, nil
+}
+
+// findDependencyCycles finds the hard cycles, which remain when optional dependencies are
+// ignored, and the soft cycles, which would be broken by dropping optional dependencies
+func findDependencyCycles(graph *catalogGraph) *DependencyCycles {
+	cycles := &DependencyCycles{
+		ProjectCount:    len(graph.projects),
+		DependencyCount: graph.edgeCount(),
+	}
+
+	required := func(dep ProjectDependency) bool { return !dep.Optional }
+	all := func(ProjectDependency) bool { return true }
+
+	hardComponent := make(map[int]int)
+	for i, component := range graph.stronglyConnectedComponents(required) {
+		if !graph.isCycle(component, required) {
+			continue
+		}
+		for _, id := range component {
+			hardComponent[id] = i + 1
+		}
+		cycles.Hard = append(cycles.Hard, DependencyCycle{
+			Projects: graph.projectsOf(component),
+			Path:     graph.shortestPath(component[0], component[0], graph.within(component, required)),
+		})
+	}
+
+	for _, component := range graph.stronglyConnectedComponents(all) {
+		if !graph.isCycle(component, all) {
+			continue
+		}
+
+		// A component that is exactly one hard cycle is not broken by optional edges
+		sameHard := hardComponent[component[0]] != 0
+		for _, id := range component {
+			if hardComponent[id] != hardComponent[component[0]] {
+				sameHard = false
+			}
+		}
+		if sameHard {
+			continue
+		}
+
+		inside := graph.within(component, all)
+		var optional []ProjectDependency
+		for _, id := range component {
+			for _, dep := range graph.edges[id] {
+				if dep.Optional && inside(dep) {
+					optional = append(optional, dep)
+				}
+			}
+		}
+
+		// Show a cycle through the first optional edge, which explains why the group exists
+		first := optional[0]
+		path := []int{first.DependentProjectID, first.ProvidingProjectID}
+		if first.ProvidingProjectID != first.DependentProjectID {
+			path = append(path[:1], graph.shortestPath(first.ProvidingProjectID, first.DependentProjectID, inside)...)
+		}
+
+		cycles.Soft = append(cycles.Soft, DependencyCycle{
+			Projects:      graph.projectsOf(component),
+			Path:          path,
+			OptionalEdges: optional,
+		})
+	}
+
+	sortCycles(cycles.Hard)
+	sortCycles(cycles.Soft)
+	return cycles
+}
+
+// isCycle reports whether a strongly connected component contains a cycle: it has more
+// than one project, or its only project depends on itself
+func (g *catalogGraph) isCycle(component []int, include func(ProjectDependency) bool) bool {
+	if len(component) > 1 {
+		return true
+	}
+	for _, dep := range g.edges[component[0]] {
+		if dep.ProvidingProjectID == component[0] && include(dep) {
+			return true
+		}
+	}
+	return false
+}
+
+// within restricts include to dependencies between projects of a component
+func (g *catalogGraph) within(component []int, include func(ProjectDependency) bool) func(ProjectDependency) bool {
+	members := make(map[int]bool)
+	for _, id := range component {
+		members[id] = true
+	}
+	return func(dep ProjectDependency) bool {
+		return include(dep) && members[dep.DependentProjectID] && members[dep.ProvidingProjectID]
+	}
+}
+
+// projectsOf returns the projects with the given IDs
+func (g *catalogGraph) projectsOf(ids []int) []Project {
+	projects := make([]Project, len(ids))
+	for i, id := range ids {
+		projects[i] = g.projects[id]
+	}
+	return projects
+}
+
+// mostCriticalTier returns the most critical tier among projects, or an unknown tier if
+// none is ranked
+func mostCriticalTier(projects []Project) CriticalityTier {
+	var best CriticalityTier
+	for _, project := range projects {
+		if tier := effectiveCriticalityTier(project); tier.Less(best) {
+			best = tier
+		}
+	}
+	return best
+}
+
+// sortCycles orders cycles by their most critical project, then by size, largest first
+func sortCycles(cycles []DependencyCycle) {
+	sort.SliceStable(cycles, func(i, j int) bool {
+		// Unranked cycles sort last
+		ti, tj := mostCriticalTier(cycles[i].Projects), mostCriticalTier(cycles[j].Projects)
+		if ti.Less(tj) || tj.Less(ti) {
+			return ti.Less(tj)
+		}
+		return len(cycles[i].Projects) > len(cycles[j].Projects)
+	})
+}
+
+// formatDependencyCycles formats the dependency cycles of the catalog for display
+func formatDependencyCycles(graph *catalogGraph, cycles *DependencyCycles) string {
+	result := "# Dependency Cycles\n\n"
+	result += fmt.Sprintf("Checked %d projects and %d dependencies.\n\n", cycles.ProjectCount, cycles.DependencyCount)
+
+	result += "## Summary\n"
+	result += fmt.Sprintf("- **Hard Cycles (required dependencies only):** %d\n", len(cycles.Hard))
+	result += fmt.Sprintf("- **Cycles Broken by Optional Dependencies:** %d\n", len(cycles.Soft))
+
+	result += fmt.Sprintf("\n## Hard Cycles (%d)\n", len(cycles.Hard))
+	if len(cycles.Hard) == 0 {
+		result += "None.\n"
+	}
+	for i, cycle := range cycles.Hard {
+		result += formatCycle(graph, i+1, cycle)
+	}
+
+	result += fmt.Sprintf("\n## Cycles Broken by Optional Dependencies (%d)\n", len(cycles.Soft))
+	if len(cycles.Soft) == 0 {
+		result += "None.\n"
+	}
+	for i, cycle := range cycles.Soft {
+		result += formatCycle(graph, i+1, cycle)
+		var edges []string
+		for _, dep := range cycle.OptionalEdges {
+			edges = append(edges, fmt.Sprintf("`%s` → `%s`", graph.permalink(dep.DependentProjectID), graph.permalink(dep.ProvidingProjectID)))
+		}
+		result += fmt.Sprintf("- **Optional Dependencies:** %s\n", strings.Join(edges, ", "))
+	}
+
+	return result
+}
+
+// formatCycle formats one cycle with its projects and an example path
+func formatCycle(graph *catalogGraph, number int, cycle DependencyCycle) string {
+	tier := mostCriticalTier(cycle.Projects)
+	size := fmt.Sprintf("%d projects", len(cycle.Projects))
+	if len(cycle.Projects) == 1 {
+		size = "1 project depending on itself"
+	}
+	result := fmt.Sprintf("\n### %d. %s, most critical: %s\n", number, size, tier)
+
+	var projects []string
+	for _, project := range cycle.Projects {
+		projects = append(projects, fmt.Sprintf("`%s` (%s, %s)", project.Permalink, effectiveCriticalityTier(project), valueOrNone(project.ProjectStakeholderOwner)))
+	}
+	result += fmt.Sprintf("- **Projects:** %s\n", strings.Join(projects, ", "))
+
+	var path []string
+	for _, id := range cycle.Path {
+		path = append(path, fmt.Sprintf("`%s`", graph.permalink(id)))
+	}
+	result += fmt.Sprintf("- **Cycle:** %s\n", strings.Join(path, " → "))
+	return result
+}
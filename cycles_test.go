@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// cycleSnapshot returns a catalog with a hard two-project cycle that an optional
+// dependency extends, a cycle that only exists through an optional dependency, a project
+// depending on itself and a cycle broken by a soft-deleted dependency
+func cycleSnapshot() *Snapshot {
+	project := func(id int, permalink, tier string) Project {
+		return testProject(id, permalink, "Team "+permalink, tier)
+	}
+	dependency := func(id, dependent, provider int, optional bool) ProjectDependency {
+		dep := testDependency(id, dependent, provider)
+		dep.Optional = optional
+		return dep
+	}
+	removed := dependency(9, 8, 7, false)
+	removed.DeletedAt = deletedAt()
+
+	return &Snapshot{
+		Projects: []Project{
+			project(1, "checkout", "Tier 1"),
+			project(2, "billing", "Tier 1"),
+			project(3, "search", "Tier 2"),
+			project(4, "indexer", "Tier 3"),
+			project(5, "emails", "Tier 2"),
+			project(6, "scheduler", "Tier 3"),
+			project(7, "reports", "Tier 3"),
+			project(8, "exports", "Tier 3"),
+		},
+		ProjectDependencies: []ProjectDependency{
+			dependency(1, 1, 2, false),
+			dependency(2, 2, 1, false),
+			dependency(3, 2, 5, false),
+			dependency(4, 5, 1, true),
+			dependency(5, 3, 4, false),
+			dependency(6, 4, 3, true),
+			dependency(7, 6, 6, false),
+			dependency(8, 7, 8, false),
+			removed,
+		},
+	}
+}
+
+func TestGetDependencyCycles(t *testing.T) {
+	service := NewProjectService(NewSnapshotSource(cycleSnapshot()), NewValidator())
+
+	result, err := service.GetDependencyCycles(context.Background())
+	if err != nil {
+		t.Fatalf("GetDependencyCycles failed: %v", err)
+	}
+
+	cycles := result.Cycles
+	if len(cycles.Hard) != 2 {
+		t.Fatalf("hard cycles = %d, want 2", len(cycles.Hard))
+	}
+	if got := len(cycles.Hard[0].Projects); got != 2 || cycles.Hard[0].Projects[0].Permalink != "checkout" {
+		t.Errorf("expected the Tier 1 checkout/billing cycle first, got %+v", cycles.Hard[0].Projects)
+	}
+	if path := cycles.Hard[1].Path; len(path) != 2 || path[0] != 6 || path[1] != 6 {
+		t.Errorf("self-dependency path = %v, want [6 6]", path)
+	}
+
+	if len(cycles.Soft) != 2 {
+		t.Fatalf("soft cycles = %d, want 2", len(cycles.Soft))
+	}
+	extended := cycles.Soft[0]
+	if len(extended.Projects) != 3 || len(extended.OptionalEdges) != 1 || extended.OptionalEdges[0].ID != 4 {
+		t.Errorf("expected the optional emails dependency to extend the checkout cycle, got %+v", extended)
+	}
+	if path := extended.Path; len(path) != 4 || path[0] != 5 || path[len(path)-1] != 5 {
+		t.Errorf("cycle through the optional dependency = %v, want a cycle from emails", path)
+	}
+
+	assertGolden(t, "dependency_cycles", result.FormattedText)
+}
+
+func TestDependencyCyclesSkipDeletedProjects(t *testing.T) {
+	retired := testProject(2, "old-billing", "Team Payments", "Tier 1")
+	retired.DeletedAt = deletedAt()
+
+	// checkout → old-billing → checkout only exists through the deleted project, and
+	// checkout → ledger → missing project 99 → checkout through a project not in the catalog
+	snapshot := &Snapshot{
+		Projects: []Project{
+			testProject(1, "checkout", "Team Checkout", "Tier 1"),
+			retired,
+			testProject(3, "ledger", "Team Payments", "Tier 1"),
+		},
+		ProjectDependencies: []ProjectDependency{
+			testDependency(1, 1, 2),
+			testDependency(2, 2, 1),
+			testDependency(3, 1, 3),
+			testDependency(4, 3, 99),
+			testDependency(5, 99, 1),
+		},
+	}
+	service := NewProjectService(NewSnapshotSource(snapshot), NewValidator())
+
+	result, err := service.GetDependencyCycles(context.Background())
+	if err != nil {
+		t.Fatalf("GetDependencyCycles failed: %v", err)
+	}
+	if len(result.Cycles.Hard) != 0 || len(result.Cycles.Soft) != 0 {
+		t.Errorf("expected no cycles through deleted or missing projects, got %+v", result.Cycles)
+	}
+	if strings.Contains(result.FormattedText, "old-billing") || strings.Contains(result.FormattedText, "project #") {
+		t.Errorf("report mentions a deleted or missing project:\n%s", result.FormattedText)
+	}
+}
+
+func TestDependencyCyclesEmptyCatalog(t *testing.T) {
+	service := NewProjectService(NewSnapshotSource(&Snapshot{}), NewValidator())
+
+	result, err := service.GetDependencyCycles(context.Background())
+	if err != nil {
+		t.Fatalf("GetDependencyCycles failed: %v", err)
+	}
+	if len(result.Cycles.Hard) != 0 || len(result.Cycles.Soft) != 0 || result.FormattedText == "" {
+		t.Errorf("expected an empty report, got %+v", result.Cycles)
+	}
+}
+
+func TestLiveSourceCatalog(t *testing.T) {
+	fake := newFakeCerebro(t)
+	for id := 1000; id < 1000+DefaultExportPageSize; id++ {
+		fake.addProject(fakeProvider(id, "Service "+string(rune('A'+id%26))))
+	}
+	source := NewLiveSource(NewCerebroClient(fake.URL(), StaticToken(fakeCerebroToken)), WithCatalogRate(1000))
+
+	catalog, err := source.Catalog(context.Background())
+	if err != nil {
+		t.Fatalf("Catalog failed: %v", err)
+	}
+	if got, want := len(catalog.Projects), 2+DefaultExportPageSize; got != want {
+		t.Errorf("project count = %d, want %d", got, want)
+	}
+	if got := len(catalog.ProjectDependencies); got != 83 {
+		t.Errorf("dependency count = %d, want 83", got)
+	}
+	if got := fake.requestCount(); got != 2 {
+		t.Errorf("requests = %d, want 2 pages", got)
+	}
+
+	// Later calls reuse the catalog until the TTL runs out
+	if again, err := source.Catalog(context.Background()); err != nil || again != catalog {
+		t.Errorf("expected the cached catalog, got %v", err)
+	}
+	if got := fake.requestCount(); got != 2 {
+		t.Errorf("requests = %d after a cached call, want 2", got)
+	}
+
+	uncached := NewLiveSource(NewCerebroClient(fake.URL(), StaticToken(fakeCerebroToken)), WithCatalogTTL(0), WithCatalogRate(1000))
+	for i := 0; i < 2; i++ {
+		if _, err := uncached.Catalog(context.Background()); err != nil {
+			t.Fatalf("Catalog failed: %v", err)
+		}
+	}
+	if got := fake.requestCount(); got != 6 {
+		t.Errorf("requests = %d, want 2 more pages per call without the cache", got)
+	}
+}
+
+func TestLiveSourceCatalogPacing(t *testing.T) {
+	fake := newFakeCerebro(t)
+	fake.setPaging(1, false, false)
+	source := NewLiveSource(NewCerebroClient(fake.URL(), StaticToken(fakeCerebroToken)), WithCatalogRate(20))
+
+	start := time.Now()
+	if _, err := source.Catalog(context.Background()); err != nil {
+		t.Fatalf("Catalog failed: %v", err)
+	}
+	// Two projects on pages of one take three requests, so two pauses of 50ms
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("catalog fetched in %v, want at least 100ms at 20 pages per second", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewLiveSource(NewCerebroClient(fake.URL(), StaticToken(fakeCerebroToken)), WithCatalogRate(0.1)).Catalog(ctx); err == nil {
+		t.Error("expected a cancelled context to stop the fetch between pages")
+	}
+}
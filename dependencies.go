@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Dependency filters by the optional flag
+const (
+	DependencyFilterAll      = "all"
+	DependencyFilterRequired = "required"
+	DependencyFilterOptional = "optional"
+)
+
+// Dependency groupings
+const (
+	DependencyGroupByNone     = "none"
+	DependencyGroupByCategory = "category"
+	DependencyGroupByTier     = "tier"
+	DependencyGroupByOwner    = "owner"
+	DependencyGroupByOptional = "optional"
+)
+
+// unresolvedGroup holds dependencies whose provider is missing or could not be fetched
+const unresolvedGroup = "Unresolved Provider"
+
+// DependencyOptions controls which dependencies GetProjectDependencies returns and how
+// they are arranged. Empty fields select the defaults.
+type DependencyOptions struct {
+	// IncludeDeleted keeps soft-deleted dependencies, which are hidden by default
+	IncludeDeleted bool
+	// Optional is one of the DependencyFilter values
+	Optional string
+	// GroupBy is one of the DependencyGroupBy values
+	GroupBy string
+	// Limit caps the number of dependencies returned, 0 for no limit
+	Limit int
+	// Cursor resumes a listing cut short by Limit or by the response budget
+	Cursor string
+}
+
+// hiddenDependencies counts the dependencies left out of a listing
+type hiddenDependencies struct {
+	deleted  int
+	filtered int
+}
+
+// includes reports whether a dependency passes the deleted and optional filters
+func (o DependencyOptions) includes(dep ProjectDependency) bool {
+	if dep.DeletedAt != nil && !o.IncludeDeleted {
+		return false
+	}
+	switch o.Optional {
+	case DependencyFilterRequired:
+		return !dep.Optional
+	case DependencyFilterOptional:
+		return dep.Optional
+	}
+	return true
+}
+
+// grouped reports whether the options group dependencies
+func (o DependencyOptions) grouped() bool {
+	return o.GroupBy != "" && o.GroupBy != DependencyGroupByNone
+}
+
+// cursorQuery describes the dependency listing of a project that a cursor continues. The
+// limit is left out because it only sizes the pages.
+func (o DependencyOptions) cursorQuery(project Project) string {
+	optional, groupBy := o.Optional, o.GroupBy
+	if optional == "" {
+		optional = DependencyFilterAll
+	}
+	if groupBy == "" {
+		groupBy = DependencyGroupByNone
+	}
+	return fmt.Sprintf("dependencies:%d:%t:%s:%s", project.ID, o.IncludeDeleted, optional, groupBy)
+}
+
+// dependencyGroup is a named run of dependencies in a grouped listing
+type dependencyGroup struct {
+	name    string
+	results []dependencyResult
+}
+
+// groupDependencyResults splits results into groups, keeping the dependency order within
+// each group. Without a grouping it returns a single unnamed group.
+func groupDependencyResults(results []dependencyResult, groupBy string) []dependencyGroup {
+	if groupBy == "" || groupBy == DependencyGroupByNone {
+		return []dependencyGroup{{results: results}}
+	}
+
+	var groups []dependencyGroup
+	index := make(map[string]int)
+	for _, res := range results {
+		name := dependencyGroupName(res, groupBy)
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, dependencyGroup{name: name})
+		}
+		groups[i].results = append(groups[i].results, res)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return dependencyGroupLess(groups[i].name, groups[j].name, groupBy)
+	})
+	return groups
+}
+
+// dependencyGroupName returns the group of a dependency for groupBy
+func dependencyGroupName(res dependencyResult, groupBy string) string {
+	if groupBy == DependencyGroupByOptional {
+		if res.dep.Optional {
+			return "Optional"
+		}
+		return "Required"
+	}
+
+	provider := res.providingProject
+	if res.err != nil || provider == nil {
+		return unresolvedGroup
+	}
+	switch groupBy {
+	case DependencyGroupByCategory:
+		return valueOrNone(provider.Category)
+	case DependencyGroupByTier:
+		return effectiveCriticalityTier(*provider).String()
+	case DependencyGroupByOwner:
+		return valueOrNone(provider.ProjectStakeholderOwner)
+	}
+	return ""
+}
+
+// dependencyGroupLess orders groups: tiers from most to least critical, required before
+// optional, and other groups by name. Groups without a value and unresolved providers
+// come last.
+func dependencyGroupLess(a, b, groupBy string) bool {
+	if last := groupSortsLast(a); last != groupSortsLast(b) {
+		return !last
+	}
+	switch groupBy {
+	case DependencyGroupByOptional:
+		return a == "Required" && b != "Required"
+	case DependencyGroupByTier:
+		ta, tb := CriticalityTier(a), CriticalityTier(b)
+		if ta.Less(tb) || tb.Less(ta) {
+			return ta.Less(tb)
+		}
+	}
+	return strings.ToLower(a) < strings.ToLower(b)
+}
+
+// groupSortsLast reports whether a group holds dependencies without a usable value
+func groupSortsLast(name string) bool {
+	return name == valueOrNone("") || name == unresolvedGroup
+}
+
+// formatDependencySummary formats the filter in effect and the size of each group
+func formatDependencySummary(groups []dependencyGroup, opts DependencyOptions, hidden hiddenDependencies) string {
+	result := ""
+	switch opts.Optional {
+	case DependencyFilterRequired:
+		result += fmt.Sprintf("**Showing:** required dependencies only (%d optional not listed)\n", hidden.filtered)
+	case DependencyFilterOptional:
+		result += fmt.Sprintf("**Showing:** optional dependencies only (%d required not listed)\n", hidden.filtered)
+	}
+	if hidden.deleted > 0 {
+		result += fmt.Sprintf("Soft-deleted dependencies hidden: %d (set include_deleted to list them).\n", hidden.deleted)
+	}
+
+	if opts.grouped() {
+		if result != "" {
+			result += "\n"
+		}
+		result += fmt.Sprintf("## Summary by %s\n\n", opts.GroupBy)
+		for _, group := range groups {
+			result += fmt.Sprintf("- **%s:** %d\n", group.name, len(group.results))
+		}
+	}
+
+	if result != "" {
+		result += "\n"
+	}
+	return result
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestGroupDependencyResults(t *testing.T) {
+	project := func(id int, category, tier, owner string) *Project {
+		return &Project{ID: id, Category: category, CriticalityTier: CriticalityTier(tier), CalculatedCriticalityTier: "Unknown", ProjectStakeholderOwner: owner}
+	}
+	results := []dependencyResult{
+		{dep: ProjectDependency{ID: 1, ProvidingProjectID: 1}, providingProject: project(1, "Service", "Tier 2", "Team B")},
+		{dep: ProjectDependency{ID: 2, ProvidingProjectID: 2, Optional: true}, providingProject: project(2, "Library", "Unknown", "")},
+		{dep: ProjectDependency{ID: 3, ProvidingProjectID: 3}},
+		{dep: ProjectDependency{ID: 4, ProvidingProjectID: 4}, providingProject: project(4, "service", "Tier 10", "Team A")},
+		{dep: ProjectDependency{ID: 5, ProvidingProjectID: 5, Optional: true}, providingProject: project(5, "Service", "Tier 0", "Team B")},
+	}
+
+	tests := []struct {
+		groupBy string
+		want    map[string][]int
+		order   []string
+	}{
+		{
+			groupBy: DependencyGroupByTier,
+			order:   []string{"Tier 0", "Tier 2", "Tier 10", "Unknown", unresolvedGroup},
+			want:    map[string][]int{"Tier 0": {5}, "Tier 2": {1}, "Tier 10": {4}, "Unknown": {2}, unresolvedGroup: {3}},
+		},
+		{
+			groupBy: DependencyGroupByOwner,
+			order:   []string{"Team A", "Team B", "(none)", unresolvedGroup},
+			want:    map[string][]int{"Team A": {4}, "Team B": {1, 5}, "(none)": {2}, unresolvedGroup: {3}},
+		},
+		{
+			groupBy: DependencyGroupByCategory,
+			order:   []string{"Library", "Service", "service", unresolvedGroup},
+			want:    map[string][]int{"Library": {2}, "Service": {1, 5}, "service": {4}, unresolvedGroup: {3}},
+		},
+		{
+			groupBy: DependencyGroupByOptional,
+			order:   []string{"Required", "Optional"},
+			want:    map[string][]int{"Required": {1, 3, 4}, "Optional": {2, 5}},
+		},
+		{
+			groupBy: DependencyGroupByNone,
+			order:   []string{""},
+			want:    map[string][]int{"": {1, 2, 3, 4, 5}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.groupBy, func(t *testing.T) {
+			groups := groupDependencyResults(results, tt.groupBy)
+			var order []string
+			got := make(map[string][]int)
+			for _, group := range groups {
+				order = append(order, group.name)
+				for _, res := range group.results {
+					got[group.name] = append(got[group.name], res.dep.ID)
+				}
+			}
+			if !reflect.DeepEqual(order, tt.order) {
+				t.Errorf("group order = %v, want %v", order, tt.order)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groups = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetProjectDependenciesResponseBudget(t *testing.T) {
+	snapshot, err := LoadSnapshot(filepath.Join("examples", "cerebro-api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const budget = 2000
+	service := NewProjectService(NewSnapshotSource(snapshot), NewValidator(), WithResponseBudget(budget))
+
+	var ids []int
+	cursor := ""
+	for pages := 1; ; pages++ {
+		result, err := service.GetProjectDependencies(context.Background(), "example-service", DependencyOptions{Cursor: cursor})
+		if err != nil {
+			t.Fatalf("GetProjectDependencies failed: %v", err)
+		}
+		if n := utf8.RuneCountInString(result.FormattedText); n > budget {
+			t.Errorf("page %d has %d characters, over the budget of %d", pages, n, budget)
+		}
+		if !strings.Contains(result.FormattedText, "Compact layout") {
+			t.Errorf("page %d: expected the compact layout", pages)
+		}
+		for _, detail := range result.Dependencies {
+			ids = append(ids, detail.Dependency.ID)
+		}
+
+		if result.NextCursor == "" {
+			if strings.Contains(result.FormattedText, "cursor `") {
+				t.Errorf("last page should not offer a cursor")
+			}
+			break
+		}
+		if !strings.Contains(result.FormattedText, "Call again with cursor `"+result.NextCursor+"`") {
+			t.Errorf("page %d does not show its continuation cursor", pages)
+		}
+		if pages > 83 {
+			t.Fatal("cursors do not make progress")
+		}
+		cursor = result.NextCursor
+	}
+
+	unlimited := NewProjectService(NewSnapshotSource(snapshot), NewValidator(), WithResponseBudget(0))
+	all, err := unlimited.GetProjectDependencies(context.Background(), "example-service", DependencyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all.NextCursor != "" || strings.Contains(all.FormattedText, "Compact layout") {
+		t.Errorf("expected no budget to list every dependency in full")
+	}
+	var want []int
+	for _, detail := range all.Dependencies {
+		want = append(want, detail.Dependency.ID)
+	}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("walking the cursors returned %d dependencies, want all %d in order", len(ids), len(want))
+	}
+
+	_, err = service.GetProjectDependencies(context.Background(), "example-service", DependencyOptions{Cursor: "bogus"})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "cursor" {
+		t.Errorf("expected a cursor ValidationError, got %v", err)
+	}
+}
+
+func TestGetProjectDependenciesLimit(t *testing.T) {
+	snapshot, err := LoadSnapshot(filepath.Join("examples", "cerebro-api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := NewProjectService(NewSnapshotSource(snapshot), NewValidator(), WithResponseBudget(0))
+
+	var sizes []int
+	cursor := ""
+	for {
+		result, err := service.GetProjectDependencies(context.Background(), "example-service", DependencyOptions{Limit: 30, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("GetProjectDependencies failed: %v", err)
+		}
+		sizes = append(sizes, len(result.Dependencies))
+		if strings.Contains(result.FormattedText, "Compact layout") {
+			t.Errorf("a limit alone should keep the full layout")
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		cursor = result.NextCursor
+	}
+	if want := []int{30, 30, 23}; !reflect.DeepEqual(sizes, want) {
+		t.Errorf("page sizes = %v, want %v", sizes, want)
+	}
+
+	result, err := service.GetProjectDependencies(context.Background(), "example-service", DependencyOptions{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Showing dependencies 1 to 10 of 83.",
+		"73 more dependencies not shown. Call again with cursor `" + result.NextCursor + "`",
+	} {
+		if !strings.Contains(result.FormattedText, want) {
+			t.Errorf("formatted text missing %q", want)
+		}
+	}
+}
+
+func TestGetProjectDependenciesCursorBinding(t *testing.T) {
+	snapshot, err := LoadSnapshot(filepath.Join("examples", "cerebro-api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := NewProjectService(NewSnapshotSource(snapshot), NewValidator(), WithResponseBudget(0))
+	ctx := context.Background()
+
+	first, err := service.GetProjectDependencies(ctx, "example-service", DependencyOptions{Limit: 10, GroupBy: DependencyGroupByTier})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The same options continue the listing, even with another page size
+	if _, err := service.GetProjectDependencies(ctx, "example-service", DependencyOptions{Limit: 20, GroupBy: DependencyGroupByTier, Cursor: first.NextCursor}); err != nil {
+		t.Errorf("continuing with the same options failed: %v", err)
+	}
+
+	for _, opts := range []DependencyOptions{
+		{Limit: 10, Cursor: first.NextCursor},
+		{Limit: 10, GroupBy: DependencyGroupByCategory, Cursor: first.NextCursor},
+		{Limit: 10, GroupBy: DependencyGroupByTier, Optional: DependencyFilterRequired, Cursor: first.NextCursor},
+		{Limit: 10, GroupBy: DependencyGroupByTier, IncludeDeleted: true, Cursor: first.NextCursor},
+	} {
+		_, err := service.GetProjectDependencies(ctx, "example-service", opts)
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "cursor" {
+			t.Errorf("reusing the cursor with %+v: expected a cursor ValidationError, got %v", opts, err)
+		}
+	}
+
+	// The last page has no next cursor, and a cursor at the end of the listing is rejected
+	last, err := service.GetProjectDependencies(ctx, "example-service", DependencyOptions{Limit: 83})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.NextCursor != "" {
+		t.Errorf("expected no next cursor when every dependency is shown, got %q", last.NextCursor)
+	}
+	project := Project{ID: 9}
+	_, err = service.GetProjectDependencies(ctx, "example-service", DependencyOptions{Cursor: encodeCursor(DependencyOptions{}.cursorQuery(project), 83)})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || !strings.Contains(validationErr.Message, "past the end") {
+		t.Errorf("expected a cursor at the end of the listing to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CatalogDiff describes how the catalog changed between two snapshots or points in time
+type CatalogDiff struct {
+	From             string
+	To               string
+	PointInTime      bool
+	AddedProjects    []Project
+	RemovedProjects  []Project
+	OwnershipChanges []OwnershipChange
+	TierChanges      []TierChange
+	AddedEdges       []DependencyEdge
+	RemovedEdges     []DependencyEdge
+}
+
+// OwnershipChange records a project whose owner or on-call stakeholder changed
+type OwnershipChange struct {
+	Project   Project
+	OldOwner  string
+	NewOwner  string
+	OldOncall string
+	NewOncall string
+}
+
+// TierChange records a project whose effective criticality tier changed
+type TierChange struct {
+	Project Project
+	OldTier CriticalityTier
+	NewTier CriticalityTier
+}
+
+// DependencyEdge is a dependency between two projects, identified by the project pair
+type DependencyEdge struct {
+	Dependency ProjectDependency
+	Dependent  string
+	Provider   string
+}
+
+// edgeKey identifies a dependency edge independently of its dependency ID, so an edge
+// that was deleted and recreated is not reported as changed
+type edgeKey struct {
+	dependentID int
+	providerID  int
+}
+
+// projectLabel returns a readable name for a project ID in a snapshot
+func projectLabel(projects map[int]Project, id int) string {
+	if project, ok := projects[id]; ok {
+		return project.Permalink
+	}
+	return fmt.Sprintf("project #%d", id)
+}
+
+// indexProjects maps project IDs to projects, skipping deleted projects
+func indexProjects(snapshot *Snapshot) map[int]Project {
+	projects := make(map[int]Project)
+	for _, project := range snapshot.Projects {
+		if project.DeletedAt == nil {
+			projects[project.ID] = project
+		}
+	}
+	return projects
+}
+
+// indexEdges maps dependency edges to their dependency, skipping deleted dependencies
+func indexEdges(snapshot *Snapshot) map[edgeKey]ProjectDependency {
+	edges := make(map[edgeKey]ProjectDependency)
+	for _, dep := range snapshot.ProjectDependencies {
+		if dep.DeletedAt == nil {
+			edges[edgeKey{dep.DependentProjectID, dep.ProvidingProjectID}] = dep
+		}
+	}
+	return edges
+}
+
+// DiffSnapshots compares two snapshots
+func DiffSnapshots(fromLabel string, from *Snapshot, toLabel string, to *Snapshot) *CatalogDiff {
+	diff := &CatalogDiff{From: fromLabel, To: toLabel}
+
+	oldProjects := indexProjects(from)
+	newProjects := indexProjects(to)
+
+	for id, project := range newProjects {
+		old, existed := oldProjects[id]
+		if !existed {
+			diff.AddedProjects = append(diff.AddedProjects, project)
+			continue
+		}
+		if old.ProjectStakeholderOwner != project.ProjectStakeholderOwner || old.ProjectStakeholderOncall != project.ProjectStakeholderOncall {
+			diff.OwnershipChanges = append(diff.OwnershipChanges, OwnershipChange{
+				Project:   project,
+				OldOwner:  old.ProjectStakeholderOwner,
+				NewOwner:  project.ProjectStakeholderOwner,
+				OldOncall: old.ProjectStakeholderOncall,
+				NewOncall: project.ProjectStakeholderOncall,
+			})
+		}
+		if oldTier, newTier := effectiveCriticalityTier(old), effectiveCriticalityTier(project); oldTier.String() != newTier.String() {
+			diff.TierChanges = append(diff.TierChanges, TierChange{Project: project, OldTier: oldTier, NewTier: newTier})
+		}
+	}
+	for id, project := range oldProjects {
+		if _, exists := newProjects[id]; !exists {
+			diff.RemovedProjects = append(diff.RemovedProjects, project)
+		}
+	}
+
+	oldEdges := indexEdges(from)
+	newEdges := indexEdges(to)
+	for key, dep := range newEdges {
+		if _, existed := oldEdges[key]; !existed {
+			diff.AddedEdges = append(diff.AddedEdges, DependencyEdge{
+				Dependency: dep,
+				Dependent:  projectLabel(newProjects, key.dependentID),
+				Provider:   projectLabel(newProjects, key.providerID),
+			})
+		}
+	}
+	for key, dep := range oldEdges {
+		if _, exists := newEdges[key]; !exists {
+			diff.RemovedEdges = append(diff.RemovedEdges, DependencyEdge{
+				Dependency: dep,
+				Dependent:  projectLabel(oldProjects, key.dependentID),
+				Provider:   projectLabel(oldProjects, key.providerID),
+			})
+		}
+	}
+
+	diff.sort()
+	return diff
+}
+
+// DiffSince reports the changes recorded in a single snapshot after since, using the
+// created_at and deleted_at timestamps of projects and dependencies. Ownership and tier
+// changes are not timestamped and cannot be detected this way.
+func DiffSince(label string, snapshot *Snapshot, since time.Time) *CatalogDiff {
+	diff := &CatalogDiff{
+		From:        since.UTC().Format(time.RFC3339),
+		To:          label,
+		PointInTime: true,
+	}
+
+	projects := make(map[int]Project)
+	for _, project := range snapshot.Projects {
+		projects[project.ID] = project
+		if happenedAfter(project.DeletedAt, since) {
+			diff.RemovedProjects = append(diff.RemovedProjects, project)
+		} else if project.DeletedAt == nil && happenedAfter(&project.CreatedAt, since) {
+			diff.AddedProjects = append(diff.AddedProjects, project)
+		}
+	}
+
+	for _, dep := range snapshot.ProjectDependencies {
+		edge := DependencyEdge{
+			Dependency: dep,
+			Dependent:  projectLabel(projects, dep.DependentProjectID),
+			Provider:   projectLabel(projects, dep.ProvidingProjectID),
+		}
+		if happenedAfter(dep.DeletedAt, since) {
+			diff.RemovedEdges = append(diff.RemovedEdges, edge)
+		} else if dep.DeletedAt == nil && happenedAfter(&dep.CreatedAt, since) {
+			diff.AddedEdges = append(diff.AddedEdges, edge)
+		}
+	}
+
+	diff.sort()
+	return diff
+}
+
+// happenedAfter reports whether a Cerebro timestamp is set and later than since
+func happenedAfter(timestamp *string, since time.Time) bool {
+	if timestamp == nil {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339, *timestamp)
+	return err == nil && t.After(since)
+}
+
+// parseSince parses an RFC 3339 timestamp or a YYYY-MM-DD date
+func parseSince(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// sort orders every list in the diff for stable output
+func (d *CatalogDiff) sort() {
+	byPermalink := func(projects []Project) {
+		sort.Slice(projects, func(i, j int) bool { return projects[i].Permalink < projects[j].Permalink })
+	}
+	byEdge := func(edges []DependencyEdge) {
+		sort.Slice(edges, func(i, j int) bool {
+			if edges[i].Dependent != edges[j].Dependent {
+				return edges[i].Dependent < edges[j].Dependent
+			}
+			return edges[i].Provider < edges[j].Provider
+		})
+	}
+
+	byPermalink(d.AddedProjects)
+	byPermalink(d.RemovedProjects)
+	sort.Slice(d.OwnershipChanges, func(i, j int) bool {
+		return d.OwnershipChanges[i].Project.Permalink < d.OwnershipChanges[j].Project.Permalink
+	})
+	sort.Slice(d.TierChanges, func(i, j int) bool {
+		return d.TierChanges[i].Project.Permalink < d.TierChanges[j].Project.Permalink
+	})
+	byEdge(d.AddedEdges)
+	byEdge(d.RemovedEdges)
+}
+
+// formatCatalogDiff formats a catalog diff for display
+func formatCatalogDiff(diff *CatalogDiff) string {
+	result := fmt.Sprintf("# Catalog Diff: %s → %s\n\n", diff.From, diff.To)
+
+	if diff.PointInTime {
+		result += fmt.Sprintf("Changes recorded in %s since %s. Ownership and criticality tier changes are not timestamped and are only detected when comparing two snapshots.\n\n", diff.To, diff.From)
+	}
+
+	result += "## Summary\n"
+	result += fmt.Sprintf("- **Projects Added:** %d\n", len(diff.AddedProjects))
+	result += fmt.Sprintf("- **Projects Removed:** %d\n", len(diff.RemovedProjects))
+	if !diff.PointInTime {
+		result += fmt.Sprintf("- **Ownership Changes:** %d\n", len(diff.OwnershipChanges))
+		result += fmt.Sprintf("- **Criticality Tier Changes:** %d\n", len(diff.TierChanges))
+	}
+	result += fmt.Sprintf("- **Dependencies Added:** %d\n", len(diff.AddedEdges))
+	result += fmt.Sprintf("- **Dependencies Removed:** %d\n", len(diff.RemovedEdges))
+
+	result += formatProjectList("Added Projects", diff.AddedProjects)
+	result += formatProjectList("Removed Projects", diff.RemovedProjects)
+
+	if !diff.PointInTime {
+		result += fmt.Sprintf("\n## Ownership Changes (%d)\n", len(diff.OwnershipChanges))
+		if len(diff.OwnershipChanges) == 0 {
+			result += "None.\n"
+		}
+		for _, change := range diff.OwnershipChanges {
+			result += fmt.Sprintf("- **%s** (`%s`):", change.Project.Name, change.Project.Permalink)
+			if change.OldOwner != change.NewOwner {
+				result += fmt.Sprintf(" owner %s → %s", valueOrNone(change.OldOwner), valueOrNone(change.NewOwner))
+			}
+			if change.OldOncall != change.NewOncall {
+				if change.OldOwner != change.NewOwner {
+					result += ";"
+				}
+				result += fmt.Sprintf(" on-call %s → %s", valueOrNone(change.OldOncall), valueOrNone(change.NewOncall))
+			}
+			result += "\n"
+		}
+
+		result += fmt.Sprintf("\n## Criticality Tier Changes (%d)\n", len(diff.TierChanges))
+		if len(diff.TierChanges) == 0 {
+			result += "None.\n"
+		}
+		for _, change := range diff.TierChanges {
+			result += fmt.Sprintf("- **%s** (`%s`): %s → %s\n", change.Project.Name, change.Project.Permalink, change.OldTier, change.NewTier)
+		}
+	}
+
+	result += formatEdgeList("Added Dependencies", diff.AddedEdges)
+	result += formatEdgeList("Removed Dependencies", diff.RemovedEdges)
+
+	return result
+}
+
+// formatProjectList formats a titled list of projects for the diff
+func formatProjectList(title string, projects []Project) string {
+	result := fmt.Sprintf("\n## %s (%d)\n", title, len(projects))
+	if len(projects) == 0 {
+		return result + "None.\n"
+	}
+	for _, project := range projects {
+		result += fmt.Sprintf("- **%s** (`%s`) - Owner: %s, Tier: %s\n",
+			project.Name, project.Permalink, valueOrNone(project.ProjectStakeholderOwner), effectiveCriticalityTier(project))
+	}
+	return result
+}
+
+// formatEdgeList formats a titled list of dependency edges for the diff
+func formatEdgeList(title string, edges []DependencyEdge) string {
+	result := fmt.Sprintf("\n## %s (%d)\n", title, len(edges))
+	if len(edges) == 0 {
+		return result + "None.\n"
+	}
+	for _, edge := range edges {
+		result += fmt.Sprintf("- `%s` → `%s`", edge.Dependent, edge.Provider)
+		if edge.Dependency.Optional {
+			result += " (optional)"
+		}
+		if edge.Dependency.Description != "" {
+			result += fmt.Sprintf(": %s", edge.Dependency.Description)
+		}
+		result += "\n"
+	}
+	return result
+}
+
+// valueOrNone returns value, or "(none)" when it is empty
+func valueOrNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
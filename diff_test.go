@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// diffSnapshots returns a week-old and a current snapshot of a small catalog
+func diffSnapshots() (*Snapshot, *Snapshot) {
+	from := &Snapshot{
+		Projects: []Project{
+			testProject(1, "checkout", "Team Payments", "Tier 1"),
+			testProject(2, "billing", "Team Payments", "Tier 2"),
+			testProject(3, "legacy-reports", "Team Data", "Tier 3"),
+		},
+		ProjectDependencies: []ProjectDependency{
+			testDependency(10, 1, 2),
+			testDependency(11, 1, 3),
+		},
+	}
+
+	checkout := testProject(1, "checkout", "Team Checkout", "Tier 1")
+	checkout.ProjectStakeholderOncall = "Checkout On-Call"
+	removedEdge := testDependency(11, 1, 3)
+	removedEdge.DeletedAt = deletedAt()
+	addedEdge := testDependency(12, 1, 4)
+	addedEdge.CreatedAt = "2025-01-28T09:00:00Z"
+	addedEdge.Optional = true
+	addedEdge.Description = "Fraud scoring"
+	removedProject := testProject(3, "legacy-reports", "Team Data", "Tier 3")
+	removedProject.DeletedAt = deletedAt()
+	fraudCheck := testProject(4, "fraud-check", "Team Risk", "Tier 2")
+	fraudCheck.CreatedAt = "2025-01-27T12:00:00Z"
+
+	to := &Snapshot{
+		Projects: []Project{
+			checkout,
+			testProject(2, "billing", "Team Payments", "Tier 1"),
+			removedProject,
+			fraudCheck,
+		},
+		ProjectDependencies: []ProjectDependency{
+			testDependency(10, 1, 2),
+			removedEdge,
+			addedEdge,
+		},
+	}
+	return from, to
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	from, to := diffSnapshots()
+	diff := DiffSnapshots("cerebro-2025-01-24.json.gz", from, "cerebro-2025-01-31.json.gz", to)
+
+	if len(diff.AddedProjects) != 1 || diff.AddedProjects[0].Permalink != "fraud-check" {
+		t.Errorf("added projects = %+v", diff.AddedProjects)
+	}
+	if len(diff.RemovedProjects) != 1 || diff.RemovedProjects[0].Permalink != "legacy-reports" {
+		t.Errorf("removed projects = %+v", diff.RemovedProjects)
+	}
+	if len(diff.OwnershipChanges) != 1 || diff.OwnershipChanges[0].NewOwner != "Team Checkout" {
+		t.Errorf("ownership changes = %+v", diff.OwnershipChanges)
+	}
+	if len(diff.TierChanges) != 1 || diff.TierChanges[0].OldTier != "Tier 2" || diff.TierChanges[0].NewTier != "Tier 1" {
+		t.Errorf("tier changes = %+v", diff.TierChanges)
+	}
+	if len(diff.AddedEdges) != 1 || diff.AddedEdges[0].Provider != "fraud-check" {
+		t.Errorf("added edges = %+v", diff.AddedEdges)
+	}
+	if len(diff.RemovedEdges) != 1 || diff.RemovedEdges[0].Provider != "legacy-reports" {
+		t.Errorf("removed edges = %+v", diff.RemovedEdges)
+	}
+
+	assertGolden(t, "catalog_diff", formatCatalogDiff(diff))
+}
+
+func TestDiffSince(t *testing.T) {
+	_, to := diffSnapshots()
+	since, err := parseSince("2025-01-27")
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := DiffSince("cerebro-2025-01-31.json.gz", to, since)
+
+	if len(diff.AddedProjects) != 1 || len(diff.RemovedProjects) != 1 {
+		t.Errorf("project changes = +%d -%d, want +1 -1", len(diff.AddedProjects), len(diff.RemovedProjects))
+	}
+	if len(diff.AddedEdges) != 1 || len(diff.RemovedEdges) != 1 {
+		t.Errorf("edge changes = +%d -%d, want +1 -1", len(diff.AddedEdges), len(diff.RemovedEdges))
+	}
+	if len(diff.OwnershipChanges) != 0 || len(diff.TierChanges) != 0 {
+		t.Error("ownership and tier changes cannot be detected from a single snapshot")
+	}
+
+	later := DiffSince("cerebro-2025-01-31.json.gz", to, since.Add(7*24*time.Hour))
+	if len(later.AddedProjects)+len(later.RemovedProjects)+len(later.AddedEdges)+len(later.RemovedEdges) != 0 {
+		t.Errorf("expected no changes after the snapshot, got %+v", later)
+	}
+}
+
+func TestGetCatalogDiff(t *testing.T) {
+	dir := t.TempDir()
+	from, to := diffSnapshots()
+	if err := WriteSnapshot(filepath.Join(dir, "cerebro-2025-01-24.json.gz"), from); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteSnapshot(filepath.Join(dir, "cerebro-2025-01-31.json.gz"), to); err != nil {
+		t.Fatal(err)
+	}
+	service := NewProjectService(NewSnapshotSource(to), NewValidator(), WithSnapshotDir(dir))
+
+	// to defaults to the newest snapshot
+	result, err := service.GetCatalogDiff(context.Background(), "cerebro-2025-01-24.json.gz", "", "")
+	if err != nil {
+		t.Fatalf("GetCatalogDiff failed: %v", err)
+	}
+	if !strings.HasPrefix(result.FormattedText, "# Catalog Diff: cerebro-2025-01-24.json.gz → cerebro-2025-01-31.json.gz") {
+		t.Errorf("unexpected heading:\n%s", result.FormattedText)
+	}
+
+	for _, tc := range []struct {
+		name              string
+		from, to, since   string
+		wantValidationErr bool
+	}{
+		{name: "nothing to compare", wantValidationErr: true},
+		{name: "from and since", from: "cerebro-2025-01-24.json.gz", since: "2025-01-27", wantValidationErr: true},
+		{name: "path traversal", from: "../secrets.json", wantValidationErr: true},
+		{name: "missing snapshot", from: "cerebro-2020-01-01.json.gz", wantValidationErr: true},
+		{name: "bad since", since: "last week", wantValidationErr: true},
+	} {
+		_, err := service.GetCatalogDiff(context.Background(), tc.from, tc.to, tc.since)
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) != tc.wantValidationErr {
+			t.Errorf("%s: got error %v", tc.name, err)
+		}
+	}
+
+	unconfigured := NewProjectService(NewSnapshotSource(to), NewValidator())
+	if _, err := unconfigured.GetCatalogDiff(context.Background(), "a.json", "", ""); err == nil {
+		t.Error("expected an error without a snapshot directory")
+	}
+}
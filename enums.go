@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// unknownValue is the sentinel Cerebro stores for values that were never set
+const unknownValue = "Unknown"
+
+// isUnknown reports whether a catalog value is missing or set to "Unknown"
+func isUnknown(value string) bool {
+	value = strings.TrimSpace(value)
+	return value == "" || strings.EqualFold(value, unknownValue)
+}
+
+// normalizedValue returns value without surrounding whitespace, or "Unknown" when it is unknown
+func normalizedValue(value string) string {
+	if isUnknown(value) {
+		return unknownValue
+	}
+	return strings.TrimSpace(value)
+}
+
+// CriticalityTier is a criticality tier such as "Tier 1". Lower tiers are more critical;
+// tiers that are unknown or not in that form rank after every numbered tier.
+type CriticalityTier string
+
+// Rank returns N for a "Tier N" criticality tier, ignoring case and spacing. ok is false
+// for tiers that are unknown or not in that form.
+func (t CriticalityTier) Rank() (rank int, ok bool) {
+	value := strings.ToLower(strings.TrimSpace(string(t)))
+	if !strings.HasPrefix(value, "tier") {
+		return 0, false
+	}
+	rank, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(value, "tier")))
+	if err != nil || rank < 0 {
+		return 0, false
+	}
+	return rank, true
+}
+
+// Known reports whether the tier is set to something other than "Unknown"
+func (t CriticalityTier) Known() bool {
+	return !isUnknown(string(t))
+}
+
+// Less reports whether t is more critical than other
+func (t CriticalityTier) Less(other CriticalityTier) bool {
+	rt, tRanked := t.Rank()
+	ro, oRanked := other.Rank()
+	if tRanked != oRanked {
+		return tRanked
+	}
+	return tRanked && rt < ro
+}
+
+// String returns the tier as "Tier N", or "Unknown" when it is not set
+func (t CriticalityTier) String() string {
+	if rank, ok := t.Rank(); ok {
+		return fmt.Sprintf("Tier %d", rank)
+	}
+	return normalizedValue(string(t))
+}
+
+// ReleaseState is the release state of a project, such as "GA" or "Beta"
+type ReleaseState string
+
+// Known reports whether the release state is set to something other than "Unknown"
+func (r ReleaseState) Known() bool {
+	return !isUnknown(string(r))
+}
+
+// String returns the release state, or "Unknown" when it is not set
+func (r ReleaseState) String() string {
+	return normalizedValue(string(r))
+}
+
+// YesNo is a Cerebro yes/no field such as in_scope_for_soc2 or tfa, which may also be
+// unknown
+type YesNo string
+
+// Yes reports whether the value is "Yes" or "true", ignoring case
+func (y YesNo) Yes() bool {
+	value := strings.TrimSpace(string(y))
+	return strings.EqualFold(value, "yes") || strings.EqualFold(value, "true")
+}
+
+// No reports whether the value is "No" or "false", ignoring case
+func (y YesNo) No() bool {
+	value := strings.TrimSpace(string(y))
+	return strings.EqualFold(value, "no") || strings.EqualFold(value, "false")
+}
+
+// Known reports whether the value is a yes or a no
+func (y YesNo) Known() bool {
+	return y.Yes() || y.No()
+}
+
+// String returns "Yes", "No", or the value as stored when it is neither
+func (y YesNo) String() string {
+	switch {
+	case y.Yes():
+		return "Yes"
+	case y.No():
+		return "No"
+	}
+	return normalizedValue(string(y))
+}
+
+// RunsOn is the platform a project runs on, such as "Kubernetes"
+type RunsOn string
+
+// Known reports whether the platform is set to something other than "Unknown"
+func (r RunsOn) Known() bool {
+	return !isUnknown(string(r))
+}
+
+// String returns the platform, or "Unknown" when it is not set
+func (r RunsOn) String() string {
+	return normalizedValue(string(r))
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+)
+
+func TestCriticalityTier(t *testing.T) {
+	tests := []struct {
+		tier   CriticalityTier
+		rank   int
+		ranked bool
+		known  bool
+		text   string
+	}{
+		{"Tier 1", 1, true, true, "Tier 1"},
+		{" tier 0 ", 0, true, true, "Tier 0"},
+		{"TIER10", 10, true, true, "Tier 10"},
+		{"Unknown", 0, false, false, "Unknown"},
+		{"unknown", 0, false, false, "Unknown"},
+		{"", 0, false, false, "Unknown"},
+		{"Tier X", 0, false, true, "Tier X"},
+		{"Tier -1", 0, false, true, "Tier -1"},
+	}
+	for _, tt := range tests {
+		rank, ranked := tt.tier.Rank()
+		if rank != tt.rank || ranked != tt.ranked {
+			t.Errorf("%q.Rank() = %d, %v; want %d, %v", tt.tier, rank, ranked, tt.rank, tt.ranked)
+		}
+		if known := tt.tier.Known(); known != tt.known {
+			t.Errorf("%q.Known() = %v, want %v", tt.tier, known, tt.known)
+		}
+		if text := tt.tier.String(); text != tt.text {
+			t.Errorf("%q.String() = %q, want %q", tt.tier, text, tt.text)
+		}
+	}
+}
+
+func TestCriticalityTierOrder(t *testing.T) {
+	tiers := []CriticalityTier{"Unknown", "Tier 10", "Tier 2", "", "Tier 0", "tier 1"}
+	sort.SliceStable(tiers, func(i, j int) bool { return tiers[i].Less(tiers[j]) })
+
+	want := []CriticalityTier{"Tier 0", "tier 1", "Tier 2", "Tier 10", "Unknown", ""}
+	for i := range want {
+		if tiers[i] != want[i] {
+			t.Fatalf("sorted tiers = %q, want %q", tiers, want)
+		}
+	}
+	if CriticalityTier("Tier 1").Less("Tier 1") || CriticalityTier("Unknown").Less("") {
+		t.Error("equal tiers must not be less than each other")
+	}
+}
+
+func TestEffectiveCriticalityTier(t *testing.T) {
+	tests := []struct {
+		declared, calculated CriticalityTier
+		want                 string
+	}{
+		{"Tier 3", "Tier 1", "Tier 1"},
+		{"Tier 3", "Unknown", "Tier 3"},
+		{"Tier 3", "unknown", "Tier 3"},
+		{"Tier 3", "", "Tier 3"},
+		{"", "", "Unknown"},
+	}
+	for _, tt := range tests {
+		project := Project{CriticalityTier: tt.declared, CalculatedCriticalityTier: tt.calculated}
+		if got := effectiveCriticalityTier(project).String(); got != tt.want {
+			t.Errorf("effectiveCriticalityTier(%q, %q) = %q, want %q", tt.declared, tt.calculated, got, tt.want)
+		}
+	}
+}
+
+func TestYesNo(t *testing.T) {
+	tests := []struct {
+		value   YesNo
+		yes, no bool
+		text    string
+	}{
+		{"Yes", true, false, "Yes"},
+		{"true", true, false, "Yes"},
+		{"NO", false, true, "No"},
+		{"Unknown", false, false, "Unknown"},
+		{"", false, false, "Unknown"},
+		{"Partially", false, false, "Partially"},
+	}
+	for _, tt := range tests {
+		if tt.value.Yes() != tt.yes || tt.value.No() != tt.no || tt.value.Known() != (tt.yes || tt.no) {
+			t.Errorf("%q: Yes() = %v, No() = %v; want %v, %v", tt.value, tt.value.Yes(), tt.value.No(), tt.yes, tt.no)
+		}
+		if text := tt.value.String(); text != tt.text {
+			t.Errorf("%q.String() = %q, want %q", tt.value, text, tt.text)
+		}
+	}
+}
+
+func TestEnumsKeepStoredValues(t *testing.T) {
+	var project Project
+	if err := json.Unmarshal([]byte(`{"criticality_tier": "tier 2", "release_state": "", "in_scope_for_soc2": "yes", "runs_on": "Kubernetes"}`), &project); err != nil {
+		t.Fatal(err)
+	}
+	if project.CriticalityTier != "tier 2" || project.InScopeForSOC2 != "yes" {
+		t.Errorf("decoding must keep values as stored, got %+v", project)
+	}
+	if project.ReleaseState.String() != "Unknown" || project.RunsOn.String() != "Kubernetes" {
+		t.Errorf("unexpected normalized values %q, %q", project.ReleaseState, project.RunsOn)
+	}
+
+	data, err := json.Marshal(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again Project
+	if err := json.Unmarshal(data, &again); err != nil || again.CriticalityTier != "tier 2" {
+		t.Errorf("snapshots must round-trip stored values, got %q (%v)", again.CriticalityTier, err)
+	}
+}
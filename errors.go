@@ -1,10 +1,18 @@
 package main
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
 
 // ProjectNotFoundError represents an error when a project is not found
 type ProjectNotFoundError struct {
 	Permalink string
+	// Source names where the project was looked up, e.g. "Cerebro"
+	Source string
 }
 
 func (e *ProjectNotFoundError) Error() string {
@@ -30,3 +38,77 @@ type APIError struct {
 func (e *APIError) Error() string {
 	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
 }
+
+// ConfigError represents one or more invalid configuration values
+type ConfigError struct {
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// toolErrorMessage converts an error into an actionable message for the caller of a tool
+func toolErrorMessage(err error) string {
+	var validationErr *ValidationError
+	var notFoundErr *ProjectNotFoundError
+	var apiErr *APIError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return fmt.Sprintf("Invalid argument %q: %s. Correct the argument and call the tool again.", validationErr.Field, validationErr.Message)
+	case errors.As(err, &notFoundErr):
+		source := notFoundErr.Source
+		if source == "" {
+			source = "the catalog"
+		}
+		return fmt.Sprintf("Project %q was not found in %s. Check the permalink spelling; permalinks are lowercase identifiers such as \"classic\".", notFoundErr.Permalink, source)
+	case errors.As(err, &apiErr):
+		switch {
+		case apiErr.StatusCode == http.StatusUnauthorized:
+			return "Cerebro rejected the API token (HTTP 401). The server operator must configure a valid Cerebro token; retrying will not help."
+		case apiErr.StatusCode == http.StatusForbidden:
+			return "The Cerebro API token is not allowed to read this data (HTTP 403). The server operator must use a token with project read access."
+		case apiErr.StatusCode == http.StatusNotFound:
+			return "The Cerebro API endpoint was not found (HTTP 404). The server operator should check the configured Cerebro base URL."
+		case apiErr.StatusCode == http.StatusTooManyRequests:
+			return "Cerebro is rate limiting requests (HTTP 429). Wait a little before calling the tool again."
+		case apiErr.StatusCode >= 500:
+			return fmt.Sprintf("Cerebro is currently unavailable (HTTP %d). Try again in a few minutes.", apiErr.StatusCode)
+		default:
+			return fmt.Sprintf("Cerebro returned an unexpected response (HTTP %d): %s", apiErr.StatusCode, apiErr.Message)
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		return "The request to Cerebro timed out. Try again in a few minutes."
+	default:
+		return fmt.Sprintf("Tool execution failed: %v", err)
+	}
+}
+
+// httpStatusForError maps an error to the HTTP status code returned by ServeHTTP
+func httpStatusForError(err error) int {
+	var validationErr *ValidationError
+	var notFoundErr *ProjectNotFoundError
+	var apiErr *APIError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest
+	case errors.As(err, &notFoundErr):
+		return http.StatusNotFound
+	case errors.As(err, &apiErr):
+		switch {
+		case apiErr.StatusCode == http.StatusTooManyRequests:
+			return http.StatusTooManyRequests
+		case apiErr.StatusCode >= 500:
+			return http.StatusServiceUnavailable
+		default:
+			// Upstream auth and routing failures are server misconfiguration, not caller errors
+			return http.StatusBadGateway
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
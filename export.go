@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Default snapshot export settings
+const (
+	DefaultExportPageSize = 100
+	DefaultExportRate     = 2.0
+	// MaxCatalogPages caps the pages fetched when paging through the whole catalog
+	MaxCatalogPages = 10000
+)
+
+// SnapshotExporter pages through the whole Cerebro catalog and writes a Snapshot
+type SnapshotExporter struct {
+	client   *CerebroClient
+	pageSize int
+	interval time.Duration
+	logf     func(format string, args ...interface{})
+}
+
+// exportCheckpoint records the progress of an interrupted export so it can resume
+type exportCheckpoint struct {
+	NextPage int `json:"next_page"`
+	// PageSize is the page size of the export; pages of another size would not line up
+	PageSize    int      `json:"page_size"`
+	LargestPage int      `json:"largest_page"`
+	Snapshot    Snapshot `json:"snapshot"`
+}
+
+// NewSnapshotExporter creates an exporter that requests pageSize projects per page and
+// makes at most rate requests per second. logf receives progress messages.
+func NewSnapshotExporter(client *CerebroClient, pageSize int, rate float64, logf func(format string, args ...interface{})) *SnapshotExporter {
+	return &SnapshotExporter{
+		client:   client,
+		pageSize: pageSize,
+		interval: pageInterval(rate),
+		logf:     logf,
+	}
+}
+
+// checkpointPath returns the path of the checkpoint kept next to output while exporting
+func checkpointPath(output string) string {
+	return output + ".partial"
+}
+
+// Export writes the catalog to output. Progress is checkpointed after every page; if a
+// checkpoint from an earlier interrupted run exists the export resumes from it.
+func (e *SnapshotExporter) Export(ctx context.Context, output string) (*Snapshot, error) {
+	checkpoint := exportCheckpoint{NextPage: 1, PageSize: e.pageSize}
+	checkpoint.Snapshot.FetchedAt = time.Now().UTC()
+
+	if err := readJSONFile(checkpointPath(output), &checkpoint); err == nil {
+		if checkpoint.PageSize != e.pageSize {
+			return nil, fmt.Errorf("export checkpoint %s was written with a page size of %d, not %d; re-run with --page-size %d or delete the checkpoint to start over",
+				checkpointPath(output), checkpoint.PageSize, e.pageSize, checkpoint.PageSize)
+		}
+		e.logf("Resuming export at page %d (%d projects already fetched)", checkpoint.NextPage, len(checkpoint.Snapshot.Projects))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read export checkpoint: %w", err)
+	}
+	pager := newCatalogPager(checkpoint.Snapshot.Projects, checkpoint.LargestPage)
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		params := CerebroAPIParameters{
+			inlines:  projectInlines,
+			includes: "dependent_project_dependencies,repositories",
+			page:     checkpoint.NextPage,
+			perPage:  e.pageSize,
+		}
+
+		response, err := e.client.makeRequest(ctx, e.client.buildURL(params))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch page %d (re-run to resume): %w", checkpoint.NextPage, err)
+		}
+
+		last, err := pager.next(checkpoint.NextPage, response)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export the catalog: %w", err)
+		}
+
+		checkpoint.Snapshot.Projects = append(checkpoint.Snapshot.Projects, response.Projects...)
+		checkpoint.Snapshot.ProjectDependencies = append(checkpoint.Snapshot.ProjectDependencies, response.ProjectDependencies...)
+		checkpoint.Snapshot.Repositories = append(checkpoint.Snapshot.Repositories, response.Repositories...)
+		checkpoint.LargestPage = pager.largest
+		checkpoint.NextPage++
+		e.logf("Fetched page %d: %d projects so far", checkpoint.NextPage-1, len(checkpoint.Snapshot.Projects))
+
+		if last {
+			break
+		}
+
+		if err := writeJSONFile(checkpointPath(output), &checkpoint); err != nil {
+			return nil, fmt.Errorf("failed to write export checkpoint: %w", err)
+		}
+
+		if err := waitForPage(ctx, ticker); err != nil {
+			return nil, fmt.Errorf("export interrupted (re-run to resume): %w", err)
+		}
+	}
+
+	snapshot := &checkpoint.Snapshot
+	snapshot.dedupe()
+	if err := WriteSnapshot(output, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	os.Remove(checkpointPath(output))
+
+	return snapshot, nil
+}
+
+// pageInterval returns the pause between page requests that keeps to rate requests per second
+func pageInterval(rate float64) time.Duration {
+	return time.Duration(float64(time.Second) / rate)
+}
+
+// waitForPage waits until ticker allows the next page request, or fails when ctx is done
+func waitForPage(ctx context.Context, ticker *time.Ticker) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-ticker.C:
+		return nil
+	}
+}
+
+// catalogPager decides when paging through the whole catalog is complete. It follows the
+// pagination metadata of the responses when there is any; otherwise the listing ends with
+// a page shorter than the longest page so far, which also works when the API caps
+// per_page below the requested size.
+type catalogPager struct {
+	seen    map[int]bool
+	largest int
+}
+
+// newCatalogPager returns a pager that resumes after the projects already fetched
+func newCatalogPager(fetched []Project, largest int) *catalogPager {
+	p := &catalogPager{seen: make(map[int]bool, len(fetched)), largest: largest}
+	for _, project := range fetched {
+		p.seen[project.ID] = true
+	}
+	return p
+}
+
+// next records a page of the listing and reports whether it was the last one. It fails
+// when the page only repeats projects fetched before or the listing does not end within
+// MaxCatalogPages pages.
+func (p *catalogPager) next(page int, response *APIResponse) (bool, error) {
+	if len(response.Projects) == 0 {
+		return true, nil
+	}
+
+	added := 0
+	for _, project := range response.Projects {
+		if !p.seen[project.ID] {
+			p.seen[project.ID] = true
+			added++
+		}
+	}
+	if added == 0 {
+		return false, fmt.Errorf("page %d only repeats projects already fetched; the API may be ignoring the page parameter", page)
+	}
+	p.largest = max(p.largest, len(response.Projects))
+
+	last, ok := paginationLastPage(response.Pagination, page, len(p.seen))
+	if !ok {
+		last = len(response.Projects) < p.largest
+	}
+	if !last && page >= MaxCatalogPages {
+		return false, fmt.Errorf("the catalog did not end within %d pages", MaxCatalogPages)
+	}
+	return last, nil
+}
+
+// paginationLastPage reports whether page is the last page according to the pagination
+// metadata of a response. ok is false when the metadata says nothing about it.
+func paginationLastPage(pagination map[string]interface{}, page, fetched int) (last, ok bool) {
+	if pages, ok := paginationNumber(pagination, "total_pages", "last_page"); ok {
+		return page >= pages, true
+	}
+	if next, present := pagination["next_page"]; present {
+		n, ok := paginationNumber(pagination, "next_page")
+		return next == nil || !ok || n <= page, true
+	}
+	if total, ok := paginationNumber(pagination, "total", "total_count", "total_entries"); ok {
+		return fetched >= total, true
+	}
+	return false, false
+}
+
+// paginationNumber returns the first of keys that the pagination metadata has as a number
+func paginationNumber(pagination map[string]interface{}, keys ...string) (int, bool) {
+	for _, key := range keys {
+		switch value := pagination[key].(type) {
+		case float64:
+			return int(value), true
+		case string:
+			if n, err := strconv.Atoi(value); err == nil {
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotExport(t *testing.T) {
+	fake := newFakeCerebro(t)
+	fake.addProject(fakeProvider(8, "Auth Service"))
+	client := NewCerebroClient(fake.URL(), StaticToken(fakeCerebroToken))
+	output := filepath.Join(t.TempDir(), "catalog.json.gz")
+
+	exporter := NewSnapshotExporter(client, 1, 1000, t.Logf)
+	if _, err := exporter.Export(context.Background(), output); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	snapshot, err := LoadSnapshot(output)
+	if err != nil {
+		t.Fatalf("failed to load exported snapshot: %v", err)
+	}
+	if snapshot.Version != SnapshotVersion {
+		t.Errorf("version = %d, want %d", snapshot.Version, SnapshotVersion)
+	}
+	if snapshot.FetchedAt.IsZero() {
+		t.Error("expected the fetch time to be recorded")
+	}
+	if got := len(snapshot.Projects); got != 3 {
+		t.Errorf("project count = %d, want 3", got)
+	}
+	if got := len(snapshot.ProjectDependencies); got != 83 {
+		t.Errorf("dependency count = %d, want 83", got)
+	}
+	if got := len(snapshot.Repositories); got != 6 {
+		t.Errorf("repository count = %d, want 6", got)
+	}
+	if len(snapshot.Projects[0].ProjectRepositoryURLs) == 0 {
+		t.Error("expected inline fields to be exported")
+	}
+}
+
+func TestSnapshotExportResumes(t *testing.T) {
+	fake := newFakeCerebro(t)
+	fake.addProject(fakeProvider(8, "Auth Service"))
+	client := NewCerebroClient(fake.URL(), StaticToken(fakeCerebroToken))
+	output := filepath.Join(t.TempDir(), "catalog.json.gz")
+
+	// Fail every request after the first page has been fetched
+	failAfterFirstPage := func(format string, args ...interface{}) {
+		if strings.HasPrefix(format, "Fetched page") {
+			fake.failWith(http.StatusServiceUnavailable)
+		}
+	}
+	if _, err := NewSnapshotExporter(client, 1, 1000, failAfterFirstPage).Export(context.Background(), output); err == nil {
+		t.Fatal("expected the first export to fail")
+	}
+	if _, err := os.Stat(checkpointPath(output)); err != nil {
+		t.Fatalf("expected a checkpoint after the failure: %v", err)
+	}
+
+	fake.failWith(0)
+	requestsBefore := fake.requestCount()
+	snapshot, err := NewSnapshotExporter(client, 1, 1000, t.Logf).Export(context.Background(), output)
+	if err != nil {
+		t.Fatalf("resumed export failed: %v", err)
+	}
+
+	// Pages 2, 3 and the final empty page 4; page 1 must not be fetched again
+	if got := fake.requestCount() - requestsBefore; got != 3 {
+		t.Errorf("resumed export made %d requests, want 3", got)
+	}
+	if got := len(snapshot.Projects); got != 3 {
+		t.Errorf("project count = %d, want 3", got)
+	}
+	if _, err := os.Stat(checkpointPath(output)); !os.IsNotExist(err) {
+		t.Errorf("expected the checkpoint to be removed after a successful export")
+	}
+}
+
+func TestSnapshotExportPaging(t *testing.T) {
+	tests := []struct {
+		name           string
+		pageSize       int
+		maxPerPage     int
+		ignorePage     bool
+		withPagination bool
+		wantRequests   int
+		wantErr        string
+	}{
+		// Three single-project pages and the empty page that ends the listing
+		{name: "short page ends the listing", pageSize: 1, wantRequests: 4},
+		// The metadata says page 3 is the last, so no empty page is needed
+		{name: "pagination metadata", pageSize: 1, withPagination: true, wantRequests: 3},
+		// Pages of 1 are shorter than the requested 2 but not the end of the catalog
+		{name: "per_page capped by the API", pageSize: 2, maxPerPage: 1, wantRequests: 4},
+		{name: "page parameter ignored", pageSize: 1, ignorePage: true, wantErr: "only repeats projects already fetched"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeCerebro(t)
+			fake.addProject(fakeProvider(8, "Auth Service"))
+			fake.setPaging(tt.maxPerPage, tt.ignorePage, tt.withPagination)
+			client := NewCerebroClient(fake.URL(), StaticToken(fakeCerebroToken))
+			output := filepath.Join(t.TempDir(), "catalog.json.gz")
+
+			snapshot, err := NewSnapshotExporter(client, tt.pageSize, 1000, t.Logf).Export(context.Background(), output)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				if _, statErr := os.Stat(output); !os.IsNotExist(statErr) {
+					t.Errorf("expected no partial snapshot to be written")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+			if got := len(snapshot.Projects); got != 3 {
+				t.Errorf("project count = %d, want 3", got)
+			}
+			if got := fake.requestCount(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestSnapshotExportRejectsResumeWithOtherPageSize(t *testing.T) {
+	fake := newFakeCerebro(t)
+	fake.addProject(fakeProvider(8, "Auth Service"))
+	client := NewCerebroClient(fake.URL(), StaticToken(fakeCerebroToken))
+	output := filepath.Join(t.TempDir(), "catalog.json.gz")
+
+	failAfterFirstPage := func(format string, args ...interface{}) {
+		if strings.HasPrefix(format, "Fetched page") {
+			fake.failWith(http.StatusServiceUnavailable)
+		}
+	}
+	if _, err := NewSnapshotExporter(client, 1, 1000, failAfterFirstPage).Export(context.Background(), output); err == nil {
+		t.Fatal("expected the first export to fail")
+	}
+
+	fake.failWith(0)
+	requestsBefore := fake.requestCount()
+	_, err := NewSnapshotExporter(client, 2, 1000, t.Logf).Export(context.Background(), output)
+	if err == nil || !strings.Contains(err.Error(), "page size of 1, not 2") {
+		t.Fatalf("expected the resume with another page size to be rejected, got %v", err)
+	}
+	if fake.requestCount() != requestsBefore {
+		t.Errorf("expected no requests after rejecting the checkpoint")
+	}
+	if _, err := os.Stat(checkpointPath(output)); err != nil {
+		t.Errorf("expected the checkpoint to be kept: %v", err)
+	}
+}
+
+func TestLiveSourceCatalogPaging(t *testing.T) {
+	fake := newFakeCerebro(t)
+	fake.setPaging(0, true, false)
+	source := NewLiveSource(NewCerebroClient(fake.URL(), StaticToken(fakeCerebroToken)), WithCatalogTTL(0), WithCatalogRate(1000))
+
+	// Ignoring the page parameter would otherwise repeat the first page forever
+	for id := 1000; id < 1000+DefaultExportPageSize; id++ {
+		fake.addProject(fakeProvider(id, "Service"))
+	}
+	if _, err := source.Catalog(context.Background()); err == nil || !strings.Contains(err.Error(), "only repeats") {
+		t.Fatalf("expected repeated pages to fail, got %v", err)
+	}
+
+	fake.setPaging(10, false, false)
+	catalog, err := source.Catalog(context.Background())
+	if err != nil {
+		t.Fatalf("Catalog failed: %v", err)
+	}
+	if got, want := len(catalog.Projects), 2+DefaultExportPageSize; got != want {
+		t.Errorf("project count = %d, want %d with per_page capped at 10", got, want)
+	}
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const fakeCerebroToken = "test-token"
+
+// inlineFields are project fields that Cerebro only returns when requested through inlines
+var inlineFields = []string{
+	"project_repository_urls",
+	"project_stakeholder_owner_name",
+	"project_stakeholder_oncall_name",
+	"link_deployment_url",
+	"link_deployment_urls",
+	"link_repository_url",
+	"link_repository_urls",
+}
+
+// fakeCerebro is an in-process Cerebro API serving the payloads in examples/cerebro-api.
+//
+// Both example payloads contain a project with the permalink "example-service"; the
+// dependency payload (ID 9) is loaded first so it wins permalink searches, while the
+// other project (ID 947) is reachable through search[id].
+type fakeCerebro struct {
+	server *httptest.Server
+	token  string
+
+	mu           sync.Mutex
+	projects     []map[string]interface{}
+	dependencies []map[string]interface{}
+	repositories []map[string]interface{}
+	failStatus   int
+	requests     []*http.Request
+
+	// maxPerPage caps per_page like an API with a lower page size limit; 0 means no cap
+	maxPerPage int
+	// ignorePage serves the first page whatever page is requested
+	ignorePage bool
+	// withPagination fills the pagination metadata of responses with the page count
+	withPagination bool
+}
+
+// newFakeCerebro starts a fake Cerebro API loaded with the example payloads
+func newFakeCerebro(t *testing.T) *fakeCerebro {
+	t.Helper()
+
+	f := &fakeCerebro{token: fakeCerebroToken}
+	for _, name := range []string{"project_dependencies_response_payload.json", "projects_response_payload.json"} {
+		f.loadPayload(t, filepath.Join("examples", "cerebro-api", name))
+	}
+
+	f.server = httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// loadPayload adds the projects, dependencies and repositories of a Cerebro response file
+func (f *fakeCerebro) loadPayload(t *testing.T, path string) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read payload %s: %v", path, err)
+	}
+
+	var payload struct {
+		Projects            []map[string]interface{} `json:"projects"`
+		ProjectDependencies []map[string]interface{} `json:"project_dependencies"`
+		Repositories        []map[string]interface{} `json:"repositories"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("failed to parse payload %s: %v", path, err)
+	}
+
+	f.projects = append(f.projects, payload.Projects...)
+	f.dependencies = append(f.dependencies, payload.ProjectDependencies...)
+	f.repositories = append(f.repositories, payload.Repositories...)
+}
+
+// URL returns the projects endpoint of the fake API
+func (f *fakeCerebro) URL() string {
+	return f.server.URL + "/projects.json"
+}
+
+// addProject adds a project to the fake catalog
+func (f *fakeCerebro) addProject(project map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.projects = append(f.projects, project)
+}
+
+// failWith makes every following request fail with status; 0 restores normal behavior
+func (f *fakeCerebro) failWith(status int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failStatus = status
+}
+
+// setPaging changes how the fake API pages through projects
+func (f *fakeCerebro) setPaging(maxPerPage int, ignorePage, withPagination bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.maxPerPage = maxPerPage
+	f.ignorePage = ignorePage
+	f.withPagination = withPagination
+}
+
+// setToken changes the token the fake API accepts, simulating a rotation
+func (f *fakeCerebro) setToken(token string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.token = token
+}
+
+// requestCount returns the number of requests served so far
+func (f *fakeCerebro) requestCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.requests)
+}
+
+func (f *fakeCerebro) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, r)
+
+	if r.URL.Path != "/projects.json" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if r.Header.Get("Authorization") != "Token "+f.token {
+		http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+	if f.failStatus != 0 {
+		http.Error(w, "injected failure", f.failStatus)
+		return
+	}
+
+	query := r.URL.Query()
+	page, perPage := query.Get("page"), query.Get("per_page")
+	if size, err := strconv.Atoi(perPage); err == nil && f.maxPerPage > 0 && size > f.maxPerPage {
+		perPage = strconv.Itoa(f.maxPerPage)
+	}
+	if f.ignorePage {
+		page = "1"
+	}
+	matches := f.searchProjects(query)
+	projects := paginate(matches, page, perPage)
+
+	pagination := map[string]interface{}{}
+	if size, err := strconv.Atoi(perPage); err == nil && size > 0 && f.withPagination {
+		pagination["total_entries"] = len(matches)
+		pagination["total_pages"] = (len(matches) + size - 1) / size
+	}
+	response := map[string]interface{}{
+		"pagination": pagination,
+		"projects":   f.applyInlines(projects, query.Get("inlines")),
+	}
+
+	includes := splitList(query.Get("includes"))
+	for _, include := range includes {
+		switch include {
+		case "dependent_project_dependencies":
+			response["project_dependencies"] = f.dependenciesOf(projects)
+		case "repositories":
+			response["repositories"] = f.repositoriesOf(projects)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// searchProjects applies search[permalink] and search[id]
+func (f *fakeCerebro) searchProjects(query map[string][]string) []map[string]interface{} {
+	var matches []map[string]interface{}
+	for _, project := range f.projects {
+		if values, ok := query["search[permalink]"]; ok && project["permalink"] != values[0] {
+			continue
+		}
+		if values, ok := query["search[id]"]; ok && fmt.Sprint(project["id"]) != values[0] {
+			continue
+		}
+		matches = append(matches, project)
+	}
+	return matches
+}
+
+// paginate returns the requested page of projects; pages start at 1
+func paginate(projects []map[string]interface{}, page, perPage string) []map[string]interface{} {
+	size, err := strconv.Atoi(perPage)
+	if err != nil || size < 1 {
+		return projects
+	}
+	number, err := strconv.Atoi(page)
+	if err != nil || number < 1 {
+		number = 1
+	}
+
+	start := (number - 1) * size
+	if start >= len(projects) {
+		return nil
+	}
+	return projects[start:min(start+size, len(projects))]
+}
+
+// applyInlines removes inline-only fields that were not requested
+func (f *fakeCerebro) applyInlines(projects []map[string]interface{}, inlines string) []map[string]interface{} {
+	requested := make(map[string]bool)
+	for _, name := range splitList(inlines) {
+		requested[name] = true
+	}
+
+	result := make([]map[string]interface{}, 0, len(projects))
+	for _, project := range projects {
+		copied := make(map[string]interface{}, len(project))
+		for key, value := range project {
+			copied[key] = value
+		}
+		for _, field := range inlineFields {
+			if !requested[field] {
+				delete(copied, field)
+			}
+		}
+		result = append(result, copied)
+	}
+	return result
+}
+
+// dependenciesOf returns the dependencies where one of the projects is the dependent
+func (f *fakeCerebro) dependenciesOf(projects []map[string]interface{}) []map[string]interface{} {
+	ids := make(map[string]bool)
+	for _, project := range projects {
+		ids[fmt.Sprint(project["id"])] = true
+	}
+
+	result := []map[string]interface{}{}
+	for _, dep := range f.dependencies {
+		if ids[fmt.Sprint(dep["dependent_project_id"])] {
+			result = append(result, dep)
+		}
+	}
+	return result
+}
+
+// repositoriesOf returns the repositories linked to the projects
+func (f *fakeCerebro) repositoriesOf(projects []map[string]interface{}) []map[string]interface{} {
+	ids := make(map[string]bool)
+	for _, project := range projects {
+		repoIDs, _ := project["repositories_ids"].([]interface{})
+		for _, id := range repoIDs {
+			ids[fmt.Sprint(id)] = true
+		}
+	}
+
+	result := []map[string]interface{}{}
+	for _, repo := range f.repositories {
+		if ids[fmt.Sprint(repo["id"])] {
+			result = append(result, repo)
+		}
+	}
+	return result
+}
+
+// fakeProvider builds a minimal providing project for dependency tests
+func fakeProvider(id int, name string) map[string]interface{} {
+	permalink := strings.ToLower(strings.ReplaceAll(name, " ", "-"))
+	return map[string]interface{}{
+		"id":                             id,
+		"name":                           name,
+		"permalink":                      permalink,
+		"description":                    name + " description",
+		"category":                       "Service",
+		"criticality_tier":               "Tier 1",
+		"calculated_criticality_tier":    "Unknown",
+		"release_state":                  "GA",
+		"slack_channel":                  permalink,
+		"project_stakeholder_owner_name": "Team " + strconv.Itoa(id),
+		"project_repository_urls":        []string{"https://github.com/example/" + permalink},
+		"link_deployment_url":            "https://deploy.example.com/" + permalink,
+		"link_deployment_urls":           []string{"https://deploy.example.com/" + permalink},
+	}
+}
+
+// newTestService creates a ProjectService talking to the fake API; catalog pages are not
+// paced so that tests stay fast
+func newTestService(f *fakeCerebro) *ProjectService {
+	client := NewCerebroClient(f.URL(), StaticToken(fakeCerebroToken))
+	return NewProjectService(NewLiveSource(client, WithCatalogRate(1000)), NewValidator())
+}
+
+// newTestServer creates a ProjectServer talking to the fake API with every tool enabled
+func newTestServer(f *fakeCerebro) *ProjectServer {
+	service := newTestService(f)
+	return NewProjectServer(service, service.validator, nil)
+}
@@ -0,0 +1,388 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"unicode/utf8"
+)
+
+// Sections of the project details output
+const (
+	DetailsFieldMetadata      = "metadata"
+	DetailsFieldCriticality   = "criticality"
+	DetailsFieldContacts      = "contacts"
+	DetailsFieldRepos         = "repos"
+	DetailsFieldDeployments   = "deployments"
+	DetailsFieldResourceUsage = "resource_usage"
+)
+
+// detailsFields lists the project details sections in output order
+var detailsFields = []string{
+	DetailsFieldMetadata,
+	DetailsFieldCriticality,
+	DetailsFieldContacts,
+	DetailsFieldRepos,
+	DetailsFieldDeployments,
+	DetailsFieldResourceUsage,
+}
+
+// formatProjectDetails formats the selected sections of the project details for display.
+// No fields selects every section.
+func (s *ProjectService) formatProjectDetails(project Project, permalink string, fields []string) string {
+	result := fmt.Sprintf("# Project Details for: %s\n", permalink)
+
+	for _, field := range detailsFields {
+		if len(fields) > 0 && !slices.Contains(fields, field) {
+			continue
+		}
+		result += "\n"
+		switch field {
+		case DetailsFieldMetadata:
+			result += formatDetailsMetadata(project)
+		case DetailsFieldCriticality:
+			result += formatDetailsCriticality(project)
+		case DetailsFieldContacts:
+			result += formatDetailsContacts(project)
+		case DetailsFieldRepos:
+			result += formatDetailsRepos(project)
+		case DetailsFieldDeployments:
+			result += formatDetailsDeployments(project)
+		case DetailsFieldResourceUsage:
+			result += formatDetailsResourceUsage(project)
+		}
+	}
+
+	return result
+}
+
+// formatDetailsMetadata formats what a project is
+func formatDetailsMetadata(project Project) string {
+	result := fmt.Sprintf("**Project Name:** %s\n", project.Name)
+	if project.Nickname != "" && project.Nickname != project.Name && project.Nickname != project.Permalink {
+		result += fmt.Sprintf("**Nickname:** %s\n", project.Nickname)
+	}
+	result += fmt.Sprintf("**Project ID:** %d\n", project.ID)
+	result += fmt.Sprintf("**Description:** %s\n", project.Description)
+	result += fmt.Sprintf("**Category:** %s\n", project.Category)
+	result += fmt.Sprintf("**Release State:** %s\n", project.ReleaseState)
+	if project.StartedOn != "" {
+		result += fmt.Sprintf("**Started On:** %s\n", project.StartedOn)
+	}
+	if project.DeletedAt != nil {
+		result += fmt.Sprintf("**Deleted At:** %s\n", *project.DeletedAt)
+	}
+	return result
+}
+
+// formatDetailsCriticality formats the criticality and compliance of a project
+func formatDetailsCriticality(project Project) string {
+	result := fmt.Sprintf("**Criticality Tier:** %s\n", effectiveCriticalityTier(project))
+	if tier := effectiveCriticalityTier(project); project.CriticalityTier.String() != tier.String() {
+		result += fmt.Sprintf("**Declared Criticality Tier:** %s\n", project.CriticalityTier)
+	}
+	result += fmt.Sprintf("**In Scope for SOC2:** %s\n", project.InScopeForSOC2)
+	if project.RequiredForCompliance {
+		result += "**Required for Compliance:** Yes\n"
+	}
+	result += fmt.Sprintf("**TFA:** %s\n", project.TFA)
+	return result
+}
+
+// formatDetailsContacts formats who owns a project and where to reach them
+func formatDetailsContacts(project Project) string {
+	result := fmt.Sprintf("**Owner:** %s\n", valueOrNone(project.ProjectStakeholderOwner))
+	result += fmt.Sprintf("**On-Call:** %s\n", valueOrNone(project.ProjectStakeholderOncall))
+	result += fmt.Sprintf("**Slack Channel:** %s\n", slackChannel(project.SlackChannel))
+	result += fmt.Sprintf("**Alert Channels (prod / staging / dev):** %s / %s / %s\n",
+		slackChannel(project.SlackChannelAlerts), slackChannel(project.SlackChannelAlertsStaging), slackChannel(project.SlackChannelAlertsDev))
+	if project.SlackChannelDev != "" {
+		result += fmt.Sprintf("**Dev Channel:** %s\n", slackChannel(project.SlackChannelDev))
+	}
+	return result
+}
+
+// formatDetailsRepos formats the repositories of a project
+func formatDetailsRepos(project Project) string {
+	if len(project.ProjectRepositoryURLs) == 0 {
+		return "No project repository URLs found.\n"
+	}
+
+	result := fmt.Sprintf("**Project Repository URLs (%d):**\n", len(project.ProjectRepositoryURLs))
+	for i, repoURL := range project.ProjectRepositoryURLs {
+		result += fmt.Sprintf("%d. %s\n", i+1, repoURL)
+	}
+	return result
+}
+
+// formatDetailsDeployments formats where and how a project is deployed
+func formatDetailsDeployments(project Project) string {
+	result := ""
+	if project.DeployTarget != "" {
+		result += fmt.Sprintf("**Deploy Target:** %s\n", project.DeployTarget)
+	}
+	if project.RunsOn.Known() {
+		result += fmt.Sprintf("**Runs On:** %s\n", project.RunsOn)
+	}
+
+	allDeploymentUrls := deploymentURLs(project)
+
+	if len(allDeploymentUrls) == 0 {
+		return result + "No deployment URLs found.\n"
+	}
+	result += fmt.Sprintf("**Project Deployment URLs (%d):**\n", len(allDeploymentUrls))
+	for i, depURL := range allDeploymentUrls {
+		result += fmt.Sprintf("%d. %s\n", i+1, depURL)
+	}
+	return result
+}
+
+// formatDetailsResourceUsage formats the resource usage recorded for a project
+func formatDetailsResourceUsage(project Project) string {
+	result := fmt.Sprintf("**CPU Usage:** %s\n", formatRawUsage(project.CPUUsage))
+	result += fmt.Sprintf("**Memory Usage:** %s\n", formatRawUsage(project.MemoryUsage))
+	return result
+}
+
+// formatDependencies formats dependencies for display, grouped as opts selects. hidden
+// counts the dependencies left out of dependencyResults.
+func (s *ProjectService) formatDependencies(project Project, dependencyResults []dependencyResult, opts DependencyOptions, hidden hiddenDependencies) string {
+	return formatDependencyPage(project, dependencyResults, opts, hidden, dependencyPage{end: len(dependencyResults)})
+}
+
+// dependencyPage selects the dependencies from start up to end of a listing and the
+// layout they are shown in
+type dependencyPage struct {
+	start   int
+	end     int
+	compact bool
+	// budget is the response budget that forced the compact layout, if any
+	budget int
+}
+
+// formatDependencyPage formats one page of a dependency listing. Group headings and the
+// summary count every dependency, not only those on the page.
+func formatDependencyPage(project Project, dependencyResults []dependencyResult, opts DependencyOptions, hidden hiddenDependencies, page dependencyPage) string {
+	result := fmt.Sprintf("# Dependencies for Project: %s\n\n", project.Name)
+	result += fmt.Sprintf("**Project ID:** %d\n", project.ID)
+	result += fmt.Sprintf("**Permalink:** %s\n", project.Permalink)
+	result += fmt.Sprintf("**Description:** %s\n\n", project.Description)
+
+	groups := groupDependencyResults(dependencyResults, opts.GroupBy)
+	result += formatDependencySummary(groups, opts, hidden)
+	if page.compact {
+		result += fmt.Sprintf("Compact layout: the full listing exceeds the response budget of %d characters.\n", page.budget)
+	}
+	if page.start > 0 || page.end < len(dependencyResults) {
+		result += fmt.Sprintf("Showing dependencies %d to %d of %d.\n", page.start+1, page.end, len(dependencyResults))
+	}
+	if page.compact || page.start > 0 || page.end < len(dependencyResults) {
+		result += "\n"
+	}
+	if !opts.grouped() {
+		result += fmt.Sprintf("## Dependencies (%d)\n\n", len(dependencyResults))
+	}
+
+	n := 0
+	for _, group := range groups {
+		first, last := n, n+len(group.results)
+		n = last
+		if last <= page.start || first >= page.end {
+			continue
+		}
+
+		if opts.grouped() {
+			result += fmt.Sprintf("## %s (%d)\n\n", group.name, len(group.results))
+		}
+		for i := max(first, page.start); i < min(last, page.end); i++ {
+			if page.compact {
+				result += formatDependencyLine(i+1, group.results[i-first])
+			} else {
+				result += formatDependencyEntry(i+1, group.results[i-first])
+			}
+		}
+		if page.compact {
+			result += "\n"
+		}
+	}
+
+	if page.end < len(dependencyResults) {
+		result += fmt.Sprintf("%d more dependencies not shown. Call again with cursor `%s` to continue.\n", len(dependencyResults)-page.end, encodeCursor(opts.cursorQuery(project), page.end))
+	}
+
+	return result
+}
+
+// fitDependencyPage lays out the dependencies from start up to end so that the text fits
+// in budget characters: in full if they fit, else one line per dependency, else as many
+// lines as fit followed by a continuation cursor. At least one dependency is always shown.
+// A budget of 0 disables the limit.
+func fitDependencyPage(project Project, dependencyResults []dependencyResult, opts DependencyOptions, hidden hiddenDependencies, start, end, budget int) (string, dependencyPage) {
+	page := dependencyPage{start: start, end: end}
+	text := formatDependencyPage(project, dependencyResults, opts, hidden, page)
+	if budget <= 0 || utf8.RuneCountInString(text) <= budget {
+		return text, page
+	}
+
+	page.compact = true
+	page.budget = budget
+	text = formatDependencyPage(project, dependencyResults, opts, hidden, page)
+	if utf8.RuneCountInString(text) <= budget || page.end-page.start <= 1 {
+		return text, page
+	}
+
+	// Find the longest page that fits; output grows with every dependency added
+	lo, hi := start+1, end-1
+	page.end = lo
+	for lo <= hi {
+		mid := (lo + hi) / 2
+		candidate := page
+		candidate.end = mid
+		if utf8.RuneCountInString(formatDependencyPage(project, dependencyResults, opts, hidden, candidate)) <= budget {
+			page.end = mid
+			lo = mid + 1
+		} else {
+			hi = mid - 1
+		}
+	}
+	return formatDependencyPage(project, dependencyResults, opts, hidden, page), page
+}
+
+// formatDependencyLine formats the nth dependency of a listing as a single line
+func formatDependencyLine(n int, res dependencyResult) string {
+	var result string
+	switch {
+	case res.err != nil:
+		result = fmt.Sprintf("%d. Project ID %d: error fetching (%v)", n, res.dep.ProvidingProjectID, res.err)
+	case res.providingProject == nil:
+		result = fmt.Sprintf("%d. Project ID %d: not found", n, res.dep.ProvidingProjectID)
+	case res.providingProject.DeletedAt != nil:
+		result = fmt.Sprintf("%d. %s (`%s`): provider deleted %s", n, res.providingProject.Name, res.providingProject.Permalink, *res.providingProject.DeletedAt)
+	default:
+		p := res.providingProject
+		result = fmt.Sprintf("%d. %s (`%s`): %s, %s, owner %s", n, p.Name, p.Permalink,
+			valueOrNone(p.Category), effectiveCriticalityTier(*p), valueOrNone(p.ProjectStakeholderOwner))
+	}
+
+	if res.dep.Optional {
+		result += ", optional"
+	}
+	if res.dep.DeletedAt != nil {
+		result += fmt.Sprintf(", dependency deleted %s", *res.dep.DeletedAt)
+	}
+	return result + "\n"
+}
+
+// formatDependencyEntry formats the nth dependency of a listing
+func formatDependencyEntry(n int, res dependencyResult) string {
+	deleted := ""
+	if res.dep.DeletedAt != nil {
+		deleted = " (Dependency Deleted)"
+	}
+
+	if res.err != nil {
+		result := fmt.Sprintf("### %d. Error fetching project ID %d%s\n", n, res.dep.ProvidingProjectID, deleted)
+		result += fmt.Sprintf("- **Error:** %v\n", res.err)
+		result += formatDependencyDeletedAt(res.dep)
+		return result + "\n"
+	}
+
+	if res.providingProject == nil {
+		result := fmt.Sprintf("### %d. Project ID %d (Not Found)%s\n", n, res.dep.ProvidingProjectID, deleted)
+		result += fmt.Sprintf("- **Dependency ID:** %d\n", res.dep.ID)
+		result += fmt.Sprintf("- **Optional:** %t\n", res.dep.Optional)
+		result += formatDependencyDeletedAt(res.dep)
+		return result + "\n"
+	}
+
+	providingProject := res.providingProject
+	if providingProject.DeletedAt != nil {
+		result := fmt.Sprintf("### %d. %s (Provider Deleted)%s\n", n, providingProject.Name, deleted)
+		result += fmt.Sprintf("- **Dependency ID:** %d\n", res.dep.ID)
+		result += fmt.Sprintf("- **Providing Project ID:** %d\n", res.dep.ProvidingProjectID)
+		result += fmt.Sprintf("- **Permalink:** %s\n", providingProject.Permalink)
+		result += fmt.Sprintf("- **Provider Deleted At:** %s\n", *providingProject.DeletedAt)
+		result += fmt.Sprintf("- **Optional:** %t\n", res.dep.Optional)
+		result += formatDependencyDeletedAt(res.dep)
+		return result + "\n"
+	}
+
+	result := fmt.Sprintf("### %d. %s%s\n", n, providingProject.Name, deleted)
+	result += fmt.Sprintf("- **Dependency ID:** %d\n", res.dep.ID)
+	result += fmt.Sprintf("- **Providing Project ID:** %d\n", res.dep.ProvidingProjectID)
+	result += fmt.Sprintf("- **Permalink:** %s\n", providingProject.Permalink)
+	result += fmt.Sprintf("- **Description:** %s\n", providingProject.Description)
+	result += fmt.Sprintf("- **Category:** %s\n", providingProject.Category)
+
+	result += fmt.Sprintf("- **Criticality Tier:** %s\n", effectiveCriticalityTier(*providingProject))
+
+	result += fmt.Sprintf("- **Release State:** %s\n", providingProject.ReleaseState)
+	result += fmt.Sprintf("- **Owner Team:** %s\n", providingProject.ProjectStakeholderOwner)
+	result += fmt.Sprintf("- **Slack Channel:** %s\n", providingProject.SlackChannel)
+
+	if len(providingProject.ProjectRepositoryURLs) == 0 {
+		result += "\nNo project repository URLs found.\n"
+	} else {
+		result += fmt.Sprintf("\n**Project Repository URLs (%d):**\n", len(providingProject.ProjectRepositoryURLs))
+		for i, repoURL := range providingProject.ProjectRepositoryURLs {
+			result += fmt.Sprintf("%d. %s\n", i+1, repoURL)
+		}
+	}
+
+	allDeploymentUrls := deploymentURLs(*providingProject)
+
+	if len(allDeploymentUrls) == 0 {
+		result += "No deployment URLs found.\n"
+	} else {
+		result += fmt.Sprintf("\n**Project Deployment URLs (%d):**\n", len(allDeploymentUrls))
+		for i, depURL := range allDeploymentUrls {
+			result += fmt.Sprintf("%d. %s\n", i+1, depURL)
+		}
+	}
+
+	result += fmt.Sprintf("\n- **Optional Dependency:** %t\n", res.dep.Optional)
+
+	if res.dep.Description != "" {
+		result += fmt.Sprintf("- **Dependency Description:** %s\n", res.dep.Description)
+	}
+	result += formatDependencyDeletedAt(res.dep)
+	return result + "\n"
+}
+
+// formatDependencyDeletedAt returns the deletion time line of a soft-deleted dependency,
+// or nothing when it is live
+func formatDependencyDeletedAt(dep ProjectDependency) string {
+	if dep.DeletedAt == nil {
+		return ""
+	}
+	return fmt.Sprintf("- **Dependency Deleted At:** %s\n", *dep.DeletedAt)
+}
+
+// effectiveCriticalityTier returns CalculatedCriticalityTier if available, otherwise CriticalityTier
+func effectiveCriticalityTier(project Project) CriticalityTier {
+	if !project.CalculatedCriticalityTier.Known() {
+		return project.CriticalityTier
+	}
+	return project.CalculatedCriticalityTier
+}
+
+// deploymentURLs collects the primary and additional deployment URLs, removing duplicates
+func deploymentURLs(project Project) []string {
+	var urls []string
+	urlSet := make(map[string]bool)
+
+	// Add primary deployment URL
+	if project.PrimaryDeploymentUrl != "" {
+		urls = append(urls, project.PrimaryDeploymentUrl)
+		urlSet[project.PrimaryDeploymentUrl] = true
+	}
+
+	// Add additional deployment URLs
+	for _, depURL := range project.AdditionalDeploymentUrls {
+		if depURL != "" && !urlSet[depURL] {
+			urls = append(urls, depURL)
+			urlSet[depURL] = true
+		}
+	}
+
+	return urls
+}
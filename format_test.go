@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// update regenerates the golden files instead of comparing against them:
+//
+//	go test ./... -run Golden -update
+var update = flag.Bool("update", false, "update golden files")
+
+// assertGolden compares got with testdata/golden/<name>.golden.md
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "golden", name+".golden.md")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("output does not match %s (run with -update to regenerate)\n%s", path, lineDiff(string(want), got))
+	}
+}
+
+// lineDiff describes the first line where two texts differ
+func lineDiff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n  want: %s\n  got:  %s", i+1, w, g)
+		}
+	}
+	return ""
+}
+
+// loadExampleProject returns the first project of an example payload
+func loadExampleProject(t *testing.T, name string) Project {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("examples", "cerebro-api", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var response APIResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatal(err)
+	}
+	return response.Projects[0]
+}
+
+func TestFormatProjectDetailsGolden(t *testing.T) {
+	example := loadExampleProject(t, "projects_response_payload.json")
+
+	missingOwner := example
+	missingOwner.ProjectStakeholderOwner = ""
+	missingOwner.ProjectStakeholderOncall = ""
+	missingOwner.SlackChannel = ""
+
+	noRepos := example
+	noRepos.ProjectRepositoryURLs = nil
+	noRepos.PrimaryDeploymentUrl = ""
+	noRepos.AdditionalDeploymentUrls = nil
+
+	duplicateURLs := example
+	duplicateURLs.PrimaryDeploymentUrl = "https://deploy.example.com/a"
+	duplicateURLs.AdditionalDeploymentUrls = []string{
+		"https://deploy.example.com/a",
+		"",
+		"https://deploy.example.com/b",
+		"https://deploy.example.com/b",
+	}
+
+	tierFallback := example
+	tierFallback.CriticalityTier = "Tier 3"
+	tierFallback.CalculatedCriticalityTier = "Unknown"
+
+	tests := []struct {
+		name    string
+		project Project
+	}{
+		{name: "details_example", project: example},
+		{name: "details_missing_owner", project: missingOwner},
+		{name: "details_no_repos_or_deployments", project: noRepos},
+		{name: "details_duplicate_deployment_urls", project: duplicateURLs},
+		{name: "details_tier_fallback", project: tierFallback},
+	}
+
+	service := &ProjectService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := service.formatProjectDetails(tt.project, tt.project.Permalink, nil)
+			// Missing values read "(none)" or "-", never an empty field
+			for _, line := range strings.Split(got, "\n") {
+				if strings.HasSuffix(line, ":** ") {
+					t.Errorf("field without a value: %q", line)
+				}
+			}
+			assertGolden(t, tt.name, got)
+		})
+	}
+
+	// Sections follow the output order, not the order they were asked for
+	assertGolden(t, "details_selected_fields", service.formatProjectDetails(example, example.Permalink,
+		[]string{DetailsFieldResourceUsage, DetailsFieldContacts}))
+}
+
+func TestFormatDependenciesGolden(t *testing.T) {
+	project := loadExampleProject(t, "project_dependencies_response_payload.json")
+
+	provider := loadExampleProject(t, "projects_response_payload.json")
+
+	bare := Project{
+		ID:                        20,
+		Name:                      "Bare Service",
+		Permalink:                 "bare-service",
+		Category:                  "Service",
+		CriticalityTier:           "Tier 2",
+		CalculatedCriticalityTier: "Unknown",
+		ReleaseState:              "Beta",
+		PrimaryDeploymentUrl:      "https://deploy.example.com/bare",
+		AdditionalDeploymentUrls:  []string{"https://deploy.example.com/bare", "https://deploy.example.com/bare"},
+	}
+
+	results := []dependencyResult{
+		{
+			dep:              ProjectDependency{ID: 1, DependentProjectID: 9, ProvidingProjectID: 947, Description: "Core API calls"},
+			providingProject: &provider,
+		},
+		{
+			dep:              ProjectDependency{ID: 2, DependentProjectID: 9, ProvidingProjectID: 20, Optional: true},
+			providingProject: &bare,
+		},
+		{
+			dep: ProjectDependency{ID: 3, DependentProjectID: 9, ProvidingProjectID: 404},
+		},
+		{
+			dep: ProjectDependency{ID: 4, DependentProjectID: 9, ProvidingProjectID: 500},
+			err: &APIError{StatusCode: 503, Message: "Service Unavailable"},
+		},
+		{
+			dep: ProjectDependency{ID: 5, DependentProjectID: 9, ProvidingProjectID: 501},
+			err: errors.New("failed to execute request: context deadline exceeded"),
+		},
+	}
+
+	service := &ProjectService{}
+	assertGolden(t, "dependencies_mixed", service.formatDependencies(project, results, DependencyOptions{}, hiddenDependencies{}))
+	assertGolden(t, "dependencies_empty", service.formatDependencies(project, nil, DependencyOptions{}, hiddenDependencies{}))
+	compact, _ := fitDependencyPage(project, results, DependencyOptions{GroupBy: DependencyGroupByOptional}, hiddenDependencies{}, 0, len(results), 1000)
+	assertGolden(t, "dependencies_compact", compact)
+	truncated, _ := fitDependencyPage(project, results, DependencyOptions{}, hiddenDependencies{}, 1, len(results), 500)
+	assertGolden(t, "dependencies_truncated", truncated)
+	assertGolden(t, "dependencies_grouped_by_tier", service.formatDependencies(project, append(results[:1:1], results[2:]...),
+		DependencyOptions{Optional: DependencyFilterRequired, GroupBy: DependencyGroupByTier}, hiddenDependencies{filtered: 1}))
+
+	gone := bare
+	gone.DeletedAt = deletedAt()
+	assertGolden(t, "dependencies_deleted", service.formatDependencies(project, []dependencyResult{
+		{
+			dep:              ProjectDependency{ID: 6, DependentProjectID: 9, ProvidingProjectID: 947, DeletedAt: deletedAt()},
+			providingProject: &provider,
+		},
+		{
+			dep:              ProjectDependency{ID: 7, DependentProjectID: 9, ProvidingProjectID: 20, Optional: true},
+			providingProject: &gone,
+		},
+		{
+			dep: ProjectDependency{ID: 8, DependentProjectID: 9, ProvidingProjectID: 404, DeletedAt: deletedAt()},
+		},
+	}, DependencyOptions{IncludeDeleted: true}, hiddenDependencies{}))
+}
@@ -2,7 +2,10 @@ module local-mcp
 
 go 1.24.2
 
-require github.com/mark3labs/mcp-go v0.33.0
+require (
+	github.com/mark3labs/mcp-go v0.33.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/google/uuid v1.6.0 // indirect
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Dependency graph limits
+const (
+	DefaultGraphDepth = 1
+	MaxGraphDepth     = 5
+	MaxGraphNodes     = 250
+)
+
+// Dependency graph output formats
+const (
+	GraphFormatMermaid = "mermaid"
+	GraphFormatDOT     = "dot"
+	GraphFormatJSON    = "json"
+)
+
+// DependencyGraph is the dependency neighborhood of a project
+type DependencyGraph struct {
+	Root      int         `json:"root"`
+	Depth     int         `json:"depth"`
+	Truncated bool        `json:"truncated"`
+	Nodes     []GraphNode `json:"nodes"`
+	Edges     []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a project in a dependency graph. Missing is set for providers that do not
+// exist in Cerebro and Error for providers that could not be fetched.
+type GraphNode struct {
+	ID        int    `json:"id"`
+	Permalink string `json:"permalink,omitempty"`
+	Name      string `json:"name"`
+	Tier      string `json:"tier,omitempty"`
+	Category  string `json:"category,omitempty"`
+	Depth     int    `json:"depth"`
+	Missing   bool   `json:"missing,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// GraphEdge is a dependency from the From project on the To project
+type GraphEdge struct {
+	From        int    `json:"from"`
+	To          int    `json:"to"`
+	Optional    bool   `json:"optional"`
+	Description string `json:"description,omitempty"`
+}
+
+// GetProjectDependencyGraph collects the dependencies of a project up to depth levels
+// deep and renders them in the given format
+func (s *ProjectService) GetProjectDependencyGraph(ctx context.Context, permalink string, depth int, format string) (*DependencyGraphResult, error) {
+	if err := s.validator.ValidateProjectPermalink(permalink); err != nil {
+		return nil, err
+	}
+
+	root, _, err := s.source.FindProject(ctx, permalink, false)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, s.projectNotFound(permalink)
+	}
+
+	graph := &DependencyGraph{Root: root.ID, Depth: depth}
+	graph.Nodes = append(graph.Nodes, projectNode(*root, 0))
+	seen := map[int]bool{root.ID: true}
+	edges := make(map[[2]int]bool)
+
+	frontier := []int{root.ID}
+	for level := 1; level <= depth && len(frontier) > 0; level++ {
+		var next []int
+		for _, id := range frontier {
+			dependencies, err := s.source.FindDependencies(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+
+			var providers []ProjectDependency
+			for _, dep := range dependencies {
+				if dep.DeletedAt != nil || edges[[2]int{id, dep.ProvidingProjectID}] {
+					continue
+				}
+				if !seen[dep.ProvidingProjectID] {
+					if len(seen) >= MaxGraphNodes {
+						graph.Truncated = true
+						continue
+					}
+					seen[dep.ProvidingProjectID] = true
+					providers = append(providers, dep)
+				}
+				edges[[2]int{id, dep.ProvidingProjectID}] = true
+				graph.Edges = append(graph.Edges, GraphEdge{
+					From:        id,
+					To:          dep.ProvidingProjectID,
+					Optional:    dep.Optional,
+					Description: dep.Description,
+				})
+			}
+
+			for _, res := range s.fetchDependenciesAsync(ctx, providers) {
+				switch {
+				case res.err != nil:
+					graph.Nodes = append(graph.Nodes, GraphNode{ID: res.dep.ProvidingProjectID, Name: fmt.Sprintf("Project ID %d", res.dep.ProvidingProjectID), Depth: level, Error: res.err.Error()})
+				case res.providingProject == nil:
+					graph.Nodes = append(graph.Nodes, GraphNode{ID: res.dep.ProvidingProjectID, Name: fmt.Sprintf("Project ID %d", res.dep.ProvidingProjectID), Depth: level, Missing: true})
+				default:
+					graph.Nodes = append(graph.Nodes, projectNode(*res.providingProject, level))
+					next = append(next, res.dep.ProvidingProjectID)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	text, err := formatDependencyGraph(graph, *root, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DependencyGraphResult{
+		Project:       *root,
+		Graph:         graph,
+		FormattedText: text,
+	}, nil
+}
+
+// projectNode creates a graph node for a project
+func projectNode(project Project, depth int) GraphNode {
+	return GraphNode{
+		ID:        project.ID,
+		Permalink: project.Permalink,
+		Name:      project.Name,
+		Tier:      effectiveCriticalityTier(project).String(),
+		Category:  project.Category,
+		Depth:     depth,
+	}
+}
+
+// label returns the display lines of a node: its name, then its tier and category
+func (n GraphNode) label() []string {
+	switch {
+	case n.Error != "":
+		return []string{n.Name, "(unavailable)"}
+	case n.Missing:
+		return []string{n.Name, "(not found)"}
+	}
+
+	var details []string
+	for _, detail := range []string{n.Tier, n.Category} {
+		if detail != "" {
+			details = append(details, detail)
+		}
+	}
+	if len(details) == 0 {
+		return []string{n.Name}
+	}
+	return []string{n.Name, strings.Join(details, " · ")}
+}
+
+// formatDependencyGraph renders a dependency graph as Mermaid, DOT or JSON
+func formatDependencyGraph(graph *DependencyGraph, root Project, format string) (string, error) {
+	if format == GraphFormatJSON {
+		data, err := json.MarshalIndent(graph, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data) + "\n", nil
+	}
+
+	result := fmt.Sprintf("# Dependency Graph for: %s\n\n", root.Name)
+	result += fmt.Sprintf("%d projects and %d dependencies, up to %d levels deep. Dashed edges are optional dependencies.\n", len(graph.Nodes), len(graph.Edges), graph.Depth)
+	if graph.Truncated {
+		result += fmt.Sprintf("\nThe graph was truncated at %d projects; request a smaller depth to see the complete neighborhood.\n", MaxGraphNodes)
+	}
+
+	if format == GraphFormatDOT {
+		return result + "\n```dot\n" + formatDOT(graph) + "```\n", nil
+	}
+	return result + "\n```mermaid\n" + formatMermaid(graph) + "```\n", nil
+}
+
+// formatMermaid renders a dependency graph as a Mermaid flowchart
+func formatMermaid(graph *DependencyGraph) string {
+	result := "graph LR\n"
+	for _, node := range graph.Nodes {
+		label := strings.ReplaceAll(strings.Join(node.label(), "<br/>"), `"`, "#quot;")
+		result += fmt.Sprintf("    p%d[\"%s\"]\n", node.ID, label)
+	}
+	for _, edge := range graph.Edges {
+		arrow := "-->"
+		if edge.Optional {
+			arrow = "-.->"
+		}
+		result += fmt.Sprintf("    p%d %s p%d\n", edge.From, arrow, edge.To)
+	}
+	result += fmt.Sprintf("    style p%d stroke-width:3px\n", graph.Root)
+	return result
+}
+
+// formatDOT renders a dependency graph in the Graphviz DOT language
+func formatDOT(graph *DependencyGraph) string {
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+	result := "digraph dependencies {\n"
+	result += "    rankdir=LR;\n"
+	result += "    node [shape=box];\n"
+	for _, node := range graph.Nodes {
+		var lines []string
+		for _, line := range node.label() {
+			lines = append(lines, escape.Replace(line))
+		}
+		attributes := fmt.Sprintf("label=\"%s\"", strings.Join(lines, `\n`))
+		if node.ID == graph.Root {
+			attributes += ", penwidth=3"
+		}
+		result += fmt.Sprintf("    p%d [%s];\n", node.ID, attributes)
+	}
+	for _, edge := range graph.Edges {
+		if edge.Optional {
+			result += fmt.Sprintf("    p%d -> p%d [style=dashed];\n", edge.From, edge.To)
+		} else {
+			result += fmt.Sprintf("    p%d -> p%d;\n", edge.From, edge.To)
+		}
+	}
+	result += "}\n"
+	return result
+}
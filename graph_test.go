@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// graphSnapshot returns a small catalog: checkout depends on billing and optionally on
+// fraud-check, billing depends on ledger and on a project missing from the catalog
+func graphSnapshot() *Snapshot {
+	optional := testDependency(3, 1, 4)
+	optional.Optional = true
+	optional.Description = "Fraud scoring"
+	removed := testDependency(5, 1, 3)
+	removed.DeletedAt = deletedAt()
+
+	return &Snapshot{
+		Projects: []Project{
+			testProject(1, "checkout", "Team Checkout", "Tier 1"),
+			testProject(2, "billing", "Team Payments", "Tier 1"),
+			testProject(3, "ledger", "Team Payments", "Tier 2"),
+			testProject(4, "fraud-check", "Team Risk", "Tier 2"),
+		},
+		ProjectDependencies: []ProjectDependency{
+			testDependency(1, 1, 2),
+			testDependency(2, 2, 3),
+			optional,
+			testDependency(4, 2, 99),
+			removed,
+		},
+	}
+}
+
+func TestGetProjectDependencyGraph(t *testing.T) {
+	service := NewProjectService(NewSnapshotSource(graphSnapshot()), NewValidator())
+	ctx := context.Background()
+
+	direct, err := service.GetProjectDependencyGraph(ctx, "checkout", 1, GraphFormatMermaid)
+	if err != nil {
+		t.Fatalf("GetProjectDependencyGraph failed: %v", err)
+	}
+	if len(direct.Graph.Nodes) != 3 || len(direct.Graph.Edges) != 2 {
+		t.Errorf("depth 1: %d nodes and %d edges, want 3 and 2", len(direct.Graph.Nodes), len(direct.Graph.Edges))
+	}
+
+	mermaid, err := service.GetProjectDependencyGraph(ctx, "checkout", 2, GraphFormatMermaid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mermaid.Graph.Nodes) != 5 || len(mermaid.Graph.Edges) != 4 {
+		t.Errorf("depth 2: %d nodes and %d edges, want 5 and 4", len(mermaid.Graph.Nodes), len(mermaid.Graph.Edges))
+	}
+	assertGolden(t, "graph_mermaid", mermaid.FormattedText)
+
+	dot, err := service.GetProjectDependencyGraph(ctx, "checkout", 2, GraphFormatDOT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "graph_dot", dot.FormattedText)
+
+	jsonResult, err := service.GetProjectDependencyGraph(ctx, "checkout", 2, GraphFormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded DependencyGraph
+	if err := json.Unmarshal([]byte(jsonResult.FormattedText), &decoded); err != nil {
+		t.Fatalf("JSON output does not parse: %v", err)
+	}
+	if decoded.Root != 1 || len(decoded.Nodes) != 5 || !decoded.Nodes[4].Missing {
+		t.Errorf("unexpected JSON graph: %+v", decoded)
+	}
+
+	_, err = service.GetProjectDependencyGraph(ctx, "nope", 1, GraphFormatJSON)
+	var notFoundErr *ProjectNotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Errorf("expected ProjectNotFoundError, got %v", err)
+	}
+}
+
+func TestGetProjectDependencyGraphTruncates(t *testing.T) {
+	snapshot := &Snapshot{Projects: []Project{testProject(1, "hub", "Team Core", "Tier 1")}}
+	for id := 2; id <= MaxGraphNodes+10; id++ {
+		snapshot.ProjectDependencies = append(snapshot.ProjectDependencies, testDependency(id, 1, id))
+	}
+	service := NewProjectService(NewSnapshotSource(snapshot), NewValidator())
+
+	result, err := service.GetProjectDependencyGraph(context.Background(), "hub", 1, GraphFormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Graph.Truncated || len(result.Graph.Nodes) != MaxGraphNodes {
+		t.Errorf("truncated = %t with %d nodes, want true with %d", result.Graph.Truncated, len(result.Graph.Nodes), MaxGraphNodes)
+	}
+}
+
+func TestLiveSourceFindDependencies(t *testing.T) {
+	fake := newFakeCerebro(t)
+	source := NewLiveSource(NewCerebroClient(fake.URL(), StaticToken(fakeCerebroToken)))
+
+	deps, err := source.FindDependencies(context.Background(), 9)
+	if err != nil {
+		t.Fatalf("FindDependencies failed: %v", err)
+	}
+	if len(deps) != 83 {
+		t.Errorf("dependency count = %d, want 83", len(deps))
+	}
+}
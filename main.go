@@ -4,23 +4,46 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
+	"os"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// version is the server version, overridden at build time with -ldflags "-X main.version=..."
+var version = "1.0.0"
+
 // Constants
 const (
-	ToolProjectGetDetails      = "project_get_details"
-	ToolProjectGetDependencies = "project_get_dependencies"
+	ToolProjectGetDetails       = "project_get_details"
+	ToolProjectGetDependencies  = "project_get_dependencies"
+	ToolProjectDependencyGraph  = "project_dependency_graph"
+	ToolDependencyCycles        = "dependency_cycles"
+	ToolCriticalityViolations   = "criticality_violations"
+	ToolDependencyPath          = "dependency_path"
+	ToolTeamGetProjects         = "team_get_projects"
+	ToolProjectIncidentContacts = "project_incident_contacts"
+	ToolCatalogAudit            = "catalog_audit"
+	ToolProjectResourceUsage    = "project_resource_usage"
+	ToolComplianceScope         = "compliance_scope"
+	ToolCatalogDiff             = "catalog_diff"
 )
 
 // ProjectServer represents the MCP server
 type ProjectServer struct {
-	service   *ProjectService
-	validator *Validator
+	service      *ProjectService
+	validator    *Validator
+	enabledTools map[string]bool
+}
+
+// toolHandler executes a tool and returns its formatted text or a typed error
+type toolHandler func(ctx context.Context, arguments map[string]interface{}) (string, error)
+
+// toolDefinition pairs an MCP tool description with its handler
+type toolDefinition struct {
+	tool    mcp.Tool
+	handler toolHandler
 }
 
 // createMCPResult creates a standardized MCP result with text content
@@ -35,80 +58,494 @@ func createMCPResult(text string) *mcp.CallToolResult {
 	}
 }
 
-// NewProjectServer creates a new Project MCP server
-func NewProjectServer(service *ProjectService, validator *Validator) *ProjectServer {
-	return &ProjectServer{
+// createMCPErrorResult creates an MCP tool error result so the message reaches the LLM
+func createMCPErrorResult(err error) *mcp.CallToolResult {
+	result := createMCPResult(toolErrorMessage(err))
+	result.IsError = true
+	return result
+}
+
+// NewProjectServer creates a new Project MCP server exposing only the enabled tools.
+// A nil enabledTools list enables every tool.
+func NewProjectServer(service *ProjectService, validator *Validator, enabledTools []string) *ProjectServer {
+	ps := &ProjectServer{
 		service:   service,
 		validator: validator,
 	}
+	if enabledTools != nil {
+		ps.enabledTools = make(map[string]bool)
+		for _, name := range enabledTools {
+			ps.enabledTools[name] = true
+		}
+	}
+	return ps
 }
 
-// SetupMCPServer configures the MCP server with all tools and resources
+// availableToolNames returns the names of every tool the server can expose
+func availableToolNames() []string {
+	var names []string
+	for _, def := range (&ProjectServer{}).toolDefinitions() {
+		names = append(names, def.tool.Name)
+	}
+	return names
+}
+
+// toolDefinitions returns every tool the server can expose, in registration order
+func (ps *ProjectServer) toolDefinitions() []toolDefinition {
+	return []toolDefinition{
+		{
+			tool: mcp.NewTool(ToolProjectGetDetails,
+				mcp.WithDescription("Get details about a project"),
+				mcp.WithString("project_permalink",
+					mcp.Description("The project permalink to retrieve details for"),
+					mcp.Required(),
+				),
+				mcp.WithArray("fields",
+					mcp.Description("Sections to return (default all): metadata, criticality, contacts, repos, deployments, resource_usage"),
+					mcp.WithStringEnumItems(detailsFields),
+				),
+			),
+			handler: ps.handleGetProjectDetails,
+		},
+		{
+			tool: mcp.NewTool(ToolProjectGetDependencies,
+				mcp.WithDescription("Get dependency information for a project"),
+				mcp.WithString("project_permalink",
+					mcp.Description("The project permalink to retrieve dependencies for"),
+					mcp.Required(),
+				),
+				mcp.WithBoolean("include_deleted",
+					mcp.Description("Also list soft-deleted dependencies with their deletion time (default false)"),
+				),
+				mcp.WithString("optional",
+					mcp.Description("List all dependencies, only required or only optional ones (default all)"),
+					mcp.Enum(DependencyFilterAll, DependencyFilterRequired, DependencyFilterOptional),
+				),
+				mcp.WithString("group_by",
+					mcp.Description("Group dependencies by a field of the providing project or by the optional flag, with a count per group (default none)"),
+					mcp.Enum(DependencyGroupByNone, DependencyGroupByCategory, DependencyGroupByTier, DependencyGroupByOwner, DependencyGroupByOptional),
+				),
+				mcp.WithNumber("limit",
+					mcp.Description(fmt.Sprintf("Maximum number of dependencies to return, 1 to %d (default all that fit in the response budget)", MaxListLimit)),
+				),
+				mcp.WithString("cursor",
+					mcp.Description("Opaque cursor from a previous response to continue the listing where it stopped"),
+				),
+			),
+			handler: ps.handleGetProjectDependencies,
+		},
+		{
+			tool: mcp.NewTool(ToolProjectDependencyGraph,
+				mcp.WithDescription("Render the dependency graph of a project as Mermaid, Graphviz DOT or a JSON node/edge list. Nodes show criticality tier and category; optional dependencies are dashed"),
+				mcp.WithString("project_permalink",
+					mcp.Description("The project permalink to graph the dependencies of"),
+					mcp.Required(),
+				),
+				mcp.WithString("format",
+					mcp.Description("Output format (default mermaid)"),
+					mcp.Enum(GraphFormatMermaid, GraphFormatDOT, GraphFormatJSON),
+				),
+				mcp.WithNumber("depth",
+					mcp.Description(fmt.Sprintf("How many levels of transitive dependencies to include, 1 to %d (default %d)", MaxGraphDepth, DefaultGraphDepth)),
+				),
+			),
+			handler: ps.handleGetProjectDependencyGraph,
+		},
+		{
+			tool: mcp.NewTool(ToolDependencyCycles,
+				mcp.WithDescription("Find circular dependencies across the whole catalog, separating cycles of required dependencies from cycles that only exist through optional dependencies"),
+			),
+			handler: ps.handleGetDependencyCycles,
+		},
+		{
+			tool: mcp.NewTool(ToolCriticalityViolations,
+				mcp.WithDescription("Find required dependencies on providers with a less critical tier (e.g. a Tier 0 project depending on a Tier 2 project), grouped by the owner team of the dependent project"),
+				mcp.WithString("team",
+					mcp.Description("Only report violations of projects owned by this team"),
+				),
+			),
+			handler: ps.handleGetCriticalityViolations,
+		},
+		{
+			tool: mcp.NewTool(ToolDependencyPath,
+				mcp.WithDescription("Explain why one project depends on another: the shortest chain of dependencies between them, with each dependency marked optional or required and its description"),
+				mcp.WithString("from",
+					mcp.Description("Permalink of the dependent project, e.g. the service that is affected"),
+					mcp.Required(),
+				),
+				mcp.WithString("to",
+					mcp.Description("Permalink of the providing project, e.g. the service that is down"),
+					mcp.Required(),
+				),
+				mcp.WithBoolean("all_paths",
+					mcp.Description(fmt.Sprintf("Also list every path that visits no project twice, up to %d paths (default false)", MaxPaths)),
+				),
+				mcp.WithNumber("max_depth",
+					mcp.Description(fmt.Sprintf("Maximum number of dependencies in the paths listed by all_paths, 1 to %d (default %d)", MaxPathMaxDepth, DefaultPathMaxDepth)),
+				),
+			),
+			handler: ps.handleGetDependencyPath,
+		},
+		{
+			tool: mcp.NewTool(ToolTeamGetProjects,
+				mcp.WithDescription("List the projects a team owns or is on call for, with criticality tier, release state, Slack channel and repository count"),
+				mcp.WithString("team",
+					mcp.Description("Team name as used for project owners and on-call stakeholders in Cerebro (case-insensitive)"),
+					mcp.Required(),
+				),
+			),
+			handler: ps.handleGetTeamProjects,
+		},
+		{
+			tool: mcp.NewTool(ToolProjectIncidentContacts,
+				mcp.WithDescription("Compile an incident contact sheet for a project and the providers of its direct required dependencies: owner, on-call, Slack and alert channels and deployment URLs"),
+				mcp.WithString("project_permalink",
+					mcp.Description("The permalink of the project having an incident"),
+					mcp.Required(),
+				),
+			),
+			handler: ps.handleGetIncidentContacts,
+		},
+		{
+			tool: mcp.NewTool(ToolCatalogAudit,
+				mcp.WithDescription("Audit catalog data quality: missing owners, on-call or Slack channels, unknown tiers or release states, missing, archived or failing repositories, and dependencies on deleted projects"),
+				mcp.WithString("team",
+					mcp.Description("Only audit projects owned by or on call with this team"),
+				),
+				mcp.WithString("category",
+					mcp.Description("Only audit projects in this category, e.g. Service"),
+				),
+			),
+			handler: ps.handleGetCatalogAudit,
+		},
+		{
+			tool: mcp.NewTool(ToolProjectResourceUsage,
+				mcp.WithDescription("Report the CPU and memory usage recorded in Cerebro for a project and the providers of its direct dependencies, or without a project the top consumers of the catalog by project, team or category"),
+				mcp.WithString("project_permalink",
+					mcp.Description("The project to report on; omit for the catalog-wide top consumers"),
+				),
+				mcp.WithString("group_by",
+					mcp.Description("How to group the catalog-wide report (default project)"),
+					mcp.Enum(ResourceGroupByProject, ResourceGroupByTeam, ResourceGroupByCategory),
+				),
+				mcp.WithString("sort_by",
+					mcp.Description("Metric to rank by (default cpu)"),
+					mcp.Enum(ResourceSortCPU, ResourceSortMemory),
+				),
+				mcp.WithNumber("top",
+					mcp.Description(fmt.Sprintf("Number of consumers in the catalog-wide report, 1 to %d (default %d)", MaxResourceTop, DefaultResourceTop)),
+				),
+			),
+			handler: ps.handleGetResourceUsage,
+		},
+		{
+			tool: mcp.NewTool(ToolComplianceScope,
+				mcp.WithDescription("List the projects in SOC2 scope or required for compliance, and flag those that depend on out-of-scope providers through required dependencies or lack an owner or on-call stakeholder"),
+				mcp.WithString("team",
+					mcp.Description("Only report projects owned by or on call with this team"),
+				),
+			),
+			handler: ps.handleGetComplianceScope,
+		},
+		{
+			tool: mcp.NewTool(ToolCatalogDiff,
+				mcp.WithDescription("Compare two catalog snapshots and report added and removed projects, ownership changes, criticality tier changes and added and removed dependencies"),
+				mcp.WithString("from",
+					mcp.Description("Snapshot file name to compare from, e.g. cerebro-2025-01-24.json.gz"),
+				),
+				mcp.WithString("to",
+					mcp.Description("Snapshot file name to compare to (default the newest snapshot)"),
+				),
+				mcp.WithString("since",
+					mcp.Description("Instead of from, report changes recorded in the to snapshot after this RFC 3339 time or YYYY-MM-DD date"),
+				),
+			),
+			handler: ps.handleCatalogDiff,
+		},
+	}
+}
+
+// enabledToolDefinitions returns the tool definitions enabled by configuration
+func (ps *ProjectServer) enabledToolDefinitions() []toolDefinition {
+	var enabled []toolDefinition
+	for _, def := range ps.toolDefinitions() {
+		if ps.enabledTools == nil || ps.enabledTools[def.tool.Name] {
+			enabled = append(enabled, def)
+		}
+	}
+	return enabled
+}
+
+// SetupMCPServer configures the MCP server with all enabled tools and resources
 func (ps *ProjectServer) SetupMCPServer() *server.MCPServer {
 	mcpServer := server.NewMCPServer(
 		"project-mcp-server",
-		"1.0.0",
+		version,
 		server.WithToolCapabilities(true),
 		server.WithLogging(),
 	)
 
-	// Add the project_get_details tool
-	mcpServer.AddTool(mcp.NewTool("project_get_details",
-		mcp.WithDescription("Get details about a project"),
-		mcp.WithString("project_permalink",
-			mcp.Description("The project permalink to retrieve details for"),
-			mcp.Required(),
-		),
-	), ps.handleGetProjectDetails)
-
-	// Add the project_get_dependencies tool
-	mcpServer.AddTool(mcp.NewTool("project_get_dependencies",
-		mcp.WithDescription("Get dependency information for a project"),
-		mcp.WithString("project_permalink",
-			mcp.Description("The project permalink to retrieve dependencies for"),
-			mcp.Required(),
-		),
-	), ps.handleGetProjectDependencies)
+	for _, def := range ps.enabledToolDefinitions() {
+		mcpServer.AddTool(def.tool, ps.mcpHandler(def.handler))
+	}
 
 	return mcpServer
 }
 
-// Tool handlers
+// toolHandlers returns the handler for each enabled tool name
+func (ps *ProjectServer) toolHandlers() map[string]toolHandler {
+	handlers := make(map[string]toolHandler)
+	for _, def := range ps.enabledToolDefinitions() {
+		handlers[def.tool.Name] = def.handler
+	}
+	return handlers
+}
 
-func (ps *ProjectServer) handleGetProjectDetails(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	arguments := request.GetArguments()
+// mcpHandler adapts a toolHandler to the MCP server, reporting failures as tool errors
+func (ps *ProjectServer) mcpHandler(handler toolHandler) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		text, err := handler(ctx, request.GetArguments())
+		if err != nil {
+			return createMCPErrorResult(err), nil
+		}
+		return createMCPResult(text), nil
+	}
+}
+
+// Tool handlers
 
+func (ps *ProjectServer) handleGetProjectDetails(ctx context.Context, arguments map[string]interface{}) (string, error) {
 	// Validate and extract project permalink
 	projectPermalink, err := ps.validator.ValidateToolArguments(arguments)
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+
+	fields, err := ps.validator.OptionalStringListArgument(arguments, "fields")
+	if err != nil {
+		return "", err
 	}
 
 	// Get project details using the service
-	result, err := ps.service.GetProjectDetails(ctx, projectPermalink)
+	result, err := ps.service.GetProjectDetails(ctx, projectPermalink, fields)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	return createMCPResult(result.FormattedText), nil
+	return result.FormattedText, nil
 }
 
-func (ps *ProjectServer) handleGetProjectDependencies(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	arguments := request.GetArguments()
-
+func (ps *ProjectServer) handleGetProjectDependencies(ctx context.Context, arguments map[string]interface{}) (string, error) {
 	// Validate and extract project permalink
 	projectPermalink, err := ps.validator.ValidateToolArguments(arguments)
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+
+	var opts DependencyOptions
+	if opts.IncludeDeleted, err = ps.validator.OptionalBoolArgument(arguments, "include_deleted"); err != nil {
+		return "", err
+	}
+	if opts.Optional, err = ps.validator.OptionalEnumArgument(arguments, "optional", DependencyFilterAll, DependencyFilterRequired, DependencyFilterOptional); err != nil {
+		return "", err
+	}
+	if opts.GroupBy, err = ps.validator.OptionalEnumArgument(arguments, "group_by", DependencyGroupByNone, DependencyGroupByCategory, DependencyGroupByTier, DependencyGroupByOwner, DependencyGroupByOptional); err != nil {
+		return "", err
+	}
+	if opts.Limit, err = ps.validator.OptionalIntArgument(arguments, "limit", 0, 1, MaxListLimit); err != nil {
+		return "", err
+	}
+	if opts.Cursor, err = ps.validator.OptionalStringArgument(arguments, "cursor"); err != nil {
+		return "", err
 	}
 
 	// Get project dependencies using the service
-	result, err := ps.service.GetProjectDependencies(ctx, projectPermalink)
+	result, err := ps.service.GetProjectDependencies(ctx, projectPermalink, opts)
+	if err != nil {
+		return "", err
+	}
+
+	return result.FormattedText, nil
+}
+
+func (ps *ProjectServer) handleGetProjectDependencyGraph(ctx context.Context, arguments map[string]interface{}) (string, error) {
+	projectPermalink, err := ps.validator.ValidateToolArguments(arguments)
+	if err != nil {
+		return "", err
+	}
+	format, err := ps.validator.OptionalEnumArgument(arguments, "format", GraphFormatMermaid, GraphFormatDOT, GraphFormatJSON)
+	if err != nil {
+		return "", err
+	}
+	depth, err := ps.validator.OptionalIntArgument(arguments, "depth", DefaultGraphDepth, 1, MaxGraphDepth)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := ps.service.GetProjectDependencyGraph(ctx, projectPermalink, depth, format)
+	if err != nil {
+		return "", err
+	}
+
+	return result.FormattedText, nil
+}
+
+func (ps *ProjectServer) handleGetDependencyCycles(ctx context.Context, arguments map[string]interface{}) (string, error) {
+	result, err := ps.service.GetDependencyCycles(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return result.FormattedText, nil
+}
+
+func (ps *ProjectServer) handleGetCriticalityViolations(ctx context.Context, arguments map[string]interface{}) (string, error) {
+	team, err := ps.validator.OptionalStringArgument(arguments, "team")
+	if err != nil {
+		return "", err
+	}
+
+	result, err := ps.service.GetCriticalityViolations(ctx, team)
+	if err != nil {
+		return "", err
+	}
+
+	return result.FormattedText, nil
+}
+
+func (ps *ProjectServer) handleGetDependencyPath(ctx context.Context, arguments map[string]interface{}) (string, error) {
+	from, err := ps.validator.OptionalStringArgument(arguments, "from")
+	if err != nil {
+		return "", err
+	}
+	to, err := ps.validator.OptionalStringArgument(arguments, "to")
+	if err != nil {
+		return "", err
+	}
+	allPaths, err := ps.validator.OptionalBoolArgument(arguments, "all_paths")
+	if err != nil {
+		return "", err
+	}
+	maxDepth, err := ps.validator.OptionalIntArgument(arguments, "max_depth", DefaultPathMaxDepth, 1, MaxPathMaxDepth)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := ps.service.GetDependencyPath(ctx, from, to, allPaths, maxDepth)
+	if err != nil {
+		return "", err
+	}
+
+	return result.FormattedText, nil
+}
+
+func (ps *ProjectServer) handleGetTeamProjects(ctx context.Context, arguments map[string]interface{}) (string, error) {
+	team, err := ps.validator.OptionalStringArgument(arguments, "team")
+	if err != nil {
+		return "", err
+	}
+
+	result, err := ps.service.GetTeamProjects(ctx, team)
+	if err != nil {
+		return "", err
+	}
+
+	return result.FormattedText, nil
+}
+
+func (ps *ProjectServer) handleGetIncidentContacts(ctx context.Context, arguments map[string]interface{}) (string, error) {
+	projectPermalink, err := ps.validator.ValidateToolArguments(arguments)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := ps.service.GetIncidentContacts(ctx, projectPermalink)
+	if err != nil {
+		return "", err
+	}
+
+	return result.FormattedText, nil
+}
+
+func (ps *ProjectServer) handleGetCatalogAudit(ctx context.Context, arguments map[string]interface{}) (string, error) {
+	team, err := ps.validator.OptionalStringArgument(arguments, "team")
+	if err != nil {
+		return "", err
+	}
+	category, err := ps.validator.OptionalStringArgument(arguments, "category")
+	if err != nil {
+		return "", err
+	}
+
+	result, err := ps.service.GetCatalogAudit(ctx, team, category)
+	if err != nil {
+		return "", err
+	}
+
+	return result.FormattedText, nil
+}
+
+func (ps *ProjectServer) handleGetResourceUsage(ctx context.Context, arguments map[string]interface{}) (string, error) {
+	permalink, err := ps.validator.OptionalStringArgument(arguments, "project_permalink")
+	if err != nil {
+		return "", err
+	}
+	groupBy, err := ps.validator.OptionalEnumArgument(arguments, "group_by", ResourceGroupByProject, ResourceGroupByTeam, ResourceGroupByCategory)
+	if err != nil {
+		return "", err
+	}
+	sortBy, err := ps.validator.OptionalEnumArgument(arguments, "sort_by", ResourceSortCPU, ResourceSortMemory)
+	if err != nil {
+		return "", err
+	}
+	top, err := ps.validator.OptionalIntArgument(arguments, "top", DefaultResourceTop, 1, MaxResourceTop)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := ps.service.GetResourceUsage(ctx, permalink, groupBy, sortBy, top)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	return createMCPResult(result.FormattedText), nil
+	return result.FormattedText, nil
+}
+
+func (ps *ProjectServer) handleGetComplianceScope(ctx context.Context, arguments map[string]interface{}) (string, error) {
+	team, err := ps.validator.OptionalStringArgument(arguments, "team")
+	if err != nil {
+		return "", err
+	}
+
+	result, err := ps.service.GetComplianceScope(ctx, team)
+	if err != nil {
+		return "", err
+	}
+
+	return result.FormattedText, nil
+}
+
+func (ps *ProjectServer) handleCatalogDiff(ctx context.Context, arguments map[string]interface{}) (string, error) {
+	from, err := ps.validator.OptionalStringArgument(arguments, "from")
+	if err != nil {
+		return "", err
+	}
+	to, err := ps.validator.OptionalStringArgument(arguments, "to")
+	if err != nil {
+		return "", err
+	}
+	since, err := ps.validator.OptionalStringArgument(arguments, "since")
+	if err != nil {
+		return "", err
+	}
+
+	// Compare snapshots from the configured snapshot directory
+	result, err := ps.service.GetCatalogDiff(ctx, from, to, since)
+	if err != nil {
+		return "", err
+	}
+
+	return result.FormattedText, nil
 }
 
 // ServeHTTP implements http.Handler to allow the MCP server to be called via HTTP
@@ -136,8 +573,8 @@ func (ps *ProjectServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Support project_get_details and project_get_dependencies tools
-	if httpReq.Tool != ToolProjectGetDetails && httpReq.Tool != ToolProjectGetDependencies {
+	handler, ok := ps.toolHandlers()[httpReq.Tool]
+	if !ok {
 		w.WriteHeader(http.StatusNotFound)
 		json.NewEncoder(w).Encode(HTTPResponse{
 			Success: false,
@@ -146,29 +583,13 @@ func (ps *ProjectServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create a proper MCP CallToolRequest
-	mcpRequest := mcp.CallToolRequest{
-		Params: mcp.CallToolParams{
-			Name:      httpReq.Tool,
-			Arguments: httpReq.Arguments,
-		},
-	}
-
-	// Call the appropriate tool handler
-	var result *mcp.CallToolResult
-	var err error
-
-	switch httpReq.Tool {
-	case ToolProjectGetDetails:
-		result, err = ps.handleGetProjectDetails(context.Background(), mcpRequest)
-	case ToolProjectGetDependencies:
-		result, err = ps.handleGetProjectDependencies(context.Background(), mcpRequest)
-	}
+	// Call the tool handler
+	text, err := handler(r.Context(), httpReq.Arguments)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(httpStatusForError(err))
 		json.NewEncoder(w).Encode(HTTPResponse{
 			Success: false,
-			Error:   fmt.Sprintf("Tool execution failed: %v", err),
+			Error:   toolErrorMessage(err),
 		})
 		return
 	}
@@ -177,52 +598,10 @@ func (ps *ProjectServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(HTTPResponse{
 		Success: true,
-		Data:    result,
+		Data:    createMCPResult(text),
 	})
 }
 
 func main() {
-	// Load configuration
-	config, err := LoadConfig()
-	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
-	}
-
-	// Create dependencies
-	client := NewCerebroClient(config.CerebroAPIBaseURL, config.CerebroToken)
-	validator := NewValidator()
-	service := NewProjectService(client, validator)
-	projectServer := NewProjectServer(service, validator)
-
-	// Setup MCP server with tools and resources
-	mcpServer := projectServer.SetupMCPServer()
-
-	// Check if HTTP_MODE environment variable is set
-	if config.HTTPMode {
-		startHTTPServer(projectServer, config)
-	} else {
-		startStdioServer(mcpServer)
-	}
-}
-
-func startHTTPServer(projectServer *ProjectServer, config *Config) {
-	// Start HTTP server
-	http.Handle(config.MCPEndpoint, projectServer)
-	log.Printf("Project MCP Server starting HTTP mode on %s", config.ServerPort)
-	log.Printf("Send POST requests to http://localhost%s%s", config.ServerPort, config.MCPEndpoint)
-	log.Printf("Available tools: %s, %s", ToolProjectGetDetails, ToolProjectGetDependencies)
-	log.Printf("Example request body: {\"tool\": \"%s\", \"arguments\": {\"project_permalink\": \"your-project\"}}", ToolProjectGetDetails)
-	log.Printf("Example dependencies request: {\"tool\": \"%s\", \"arguments\": {\"project_permalink\": \"your-project\"}}", ToolProjectGetDependencies)
-	if err := http.ListenAndServe(config.ServerPort, nil); err != nil {
-		log.Fatalf("HTTP server error: %v", err)
-	}
-}
-
-func startStdioServer(mcpServer *server.MCPServer) {
-	// Start stdio server (default mode)
-	log.Printf("Project MCP Server starting in stdio mode")
-	log.Printf("Set HTTP_MODE=true to run in HTTP mode instead")
-	if err := server.ServeStdio(mcpServer); err != nil {
-		log.Fatalf("Server error: %v", err)
-	}
+	os.Exit(runCLI(os.Args[1:], os.Stdout, os.Stderr))
 }
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// callTool invokes a tool through the MCP handler adapter
+func callTool(t *testing.T, ps *ProjectServer, name string, arguments map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+
+	handler, ok := ps.toolHandlers()[name]
+	if !ok {
+		t.Fatalf("tool %s is not registered", name)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = arguments
+
+	result, err := ps.mcpHandler(handler)(context.Background(), request)
+	if err != nil {
+		t.Fatalf("tool handlers must not return Go errors, got %v", err)
+	}
+	return result
+}
+
+// resultText returns the text of the first content item of a tool result
+func resultText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+
+	if len(result.Content) == 0 {
+		t.Fatal("tool result has no content")
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+	return text.Text
+}
+
+func TestToolHandlers(t *testing.T) {
+	tests := []struct {
+		name      string
+		tool      string
+		arguments map[string]interface{}
+		failWith  int
+		wantError bool
+		wantText  string
+	}{
+		{
+			name:      "details",
+			tool:      ToolProjectGetDetails,
+			arguments: map[string]interface{}{"project_permalink": "example-service"},
+			wantText:  "# Project Details for: example-service",
+		},
+		{
+			name:      "dependencies",
+			tool:      ToolProjectGetDependencies,
+			arguments: map[string]interface{}{"project_permalink": "example-service"},
+			wantText:  "## Dependencies (83)",
+		},
+		{
+			name:      "optional dependencies only",
+			tool:      ToolProjectGetDependencies,
+			arguments: map[string]interface{}{"project_permalink": "example-service", "optional": "optional"},
+			wantText:  "**Showing:** optional dependencies only (82 required not listed)",
+		},
+		{
+			name:      "selected details sections",
+			tool:      ToolProjectGetDetails,
+			arguments: map[string]interface{}{"project_permalink": "example-service", "fields": []interface{}{"contacts"}},
+			wantText:  "**Dev Channel:** #example-service-dev-team",
+		},
+		{
+			name:      "unknown details section",
+			tool:      ToolProjectGetDetails,
+			arguments: map[string]interface{}{"project_permalink": "example-service", "fields": []interface{}{"history"}},
+			wantError: true,
+			wantText:  `contains "history"`,
+		},
+		{
+			name:      "project resource usage",
+			tool:      ToolProjectResourceUsage,
+			arguments: map[string]interface{}{"project_permalink": "example-service"},
+			wantText:  "## Direct Dependencies (83)",
+		},
+		{
+			name:      "catalog resource usage",
+			tool:      ToolProjectResourceUsage,
+			arguments: map[string]interface{}{"group_by": "category", "sort_by": "memory", "top": float64(5)},
+			wantText:  "# Top Resource Consumers by Category",
+		},
+		{
+			name:      "compliance scope",
+			tool:      ToolComplianceScope,
+			arguments: map[string]interface{}{},
+			wantText:  "# Compliance Scope",
+		},
+		{
+			name:      "first page of dependencies",
+			tool:      ToolProjectGetDependencies,
+			arguments: map[string]interface{}{"project_permalink": "example-service", "limit": float64(5)},
+			wantText:  "Showing dependencies 1 to 5 of 83.",
+		},
+		{
+			name:      "limit out of range",
+			tool:      ToolProjectGetDependencies,
+			arguments: map[string]interface{}{"project_permalink": "example-service", "limit": float64(0)},
+			wantError: true,
+			wantText:  "limit",
+		},
+		{
+			name:      "unknown grouping",
+			tool:      ToolProjectGetDependencies,
+			arguments: map[string]interface{}{"project_permalink": "example-service", "group_by": "color"},
+			wantError: true,
+			wantText:  "group_by",
+		},
+		{
+			name:      "missing permalink",
+			tool:      ToolProjectGetDetails,
+			arguments: map[string]interface{}{},
+			wantError: true,
+			wantText:  `Invalid argument "project_permalink"`,
+		},
+		{
+			name:      "unknown project",
+			tool:      ToolProjectGetDependencies,
+			arguments: map[string]interface{}{"project_permalink": "nope"},
+			wantError: true,
+			wantText:  `Project "nope" was not found`,
+		},
+		{
+			name:      "upstream outage",
+			tool:      ToolProjectGetDetails,
+			arguments: map[string]interface{}{"project_permalink": "example-service"},
+			failWith:  http.StatusBadGateway,
+			wantError: true,
+			wantText:  "Cerebro is currently unavailable (HTTP 502)",
+		},
+		{
+			name:      "dependency graph",
+			tool:      ToolProjectDependencyGraph,
+			arguments: map[string]interface{}{"project_permalink": "example-service", "format": "dot"},
+			wantText:  "digraph dependencies {",
+		},
+		{
+			name:      "dependency graph with bad depth",
+			tool:      ToolProjectDependencyGraph,
+			arguments: map[string]interface{}{"project_permalink": "example-service", "depth": float64(9)},
+			wantError: true,
+			wantText:  `Invalid argument "depth": must be between 1 and 5`,
+		},
+		{
+			name:      "dependency path without to",
+			tool:      ToolDependencyPath,
+			arguments: map[string]interface{}{"from": "example-service"},
+			wantError: true,
+			wantText:  `Invalid argument "to": cannot be empty`,
+		},
+		{
+			name:      "catalog diff without snapshot directory",
+			tool:      ToolCatalogDiff,
+			arguments: map[string]interface{}{"since": "2025-01-01"},
+			wantError: true,
+			wantText:  "catalog diffs need a snapshot directory",
+		},
+		{
+			name:      "catalog diff with non-string argument",
+			tool:      ToolCatalogDiff,
+			arguments: map[string]interface{}{"from": 7},
+			wantError: true,
+			wantText:  `Invalid argument "from"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeCerebro(t)
+			fake.failWith(tt.failWith)
+
+			result := callTool(t, newTestServer(fake), tt.tool, tt.arguments)
+
+			if result.IsError != tt.wantError {
+				t.Errorf("IsError = %t, want %t", result.IsError, tt.wantError)
+			}
+			if text := resultText(t, result); !strings.Contains(text, tt.wantText) {
+				t.Errorf("result text %q does not contain %q", text, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestEnabledTools(t *testing.T) {
+	fake := newFakeCerebro(t)
+	service := newTestService(fake)
+	ps := NewProjectServer(service, service.validator, []string{ToolProjectGetDetails})
+
+	handlers := ps.toolHandlers()
+	if _, ok := handlers[ToolProjectGetDetails]; !ok {
+		t.Errorf("expected %s to be enabled", ToolProjectGetDetails)
+	}
+	if _, ok := handlers[ToolProjectGetDependencies]; ok {
+		t.Errorf("expected %s to be disabled", ToolProjectGetDependencies)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		failWith   int
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name:       "success",
+			method:     http.MethodPost,
+			body:       `{"tool": "project_get_details", "arguments": {"project_permalink": "example-service"}}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+			wantError:  "Only POST method is allowed",
+		},
+		{
+			name:       "invalid JSON",
+			method:     http.MethodPost,
+			body:       `{`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "Failed to parse request",
+		},
+		{
+			name:       "unknown tool",
+			method:     http.MethodPost,
+			body:       `{"tool": "invalid_tool"}`,
+			wantStatus: http.StatusNotFound,
+			wantError:  "Tool not found: invalid_tool",
+		},
+		{
+			name:       "validation error",
+			method:     http.MethodPost,
+			body:       `{"tool": "project_get_details", "arguments": {"project_permalink": ""}}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "Invalid argument",
+		},
+		{
+			name:       "project not found",
+			method:     http.MethodPost,
+			body:       `{"tool": "project_get_dependencies", "arguments": {"project_permalink": "nope"}}`,
+			wantStatus: http.StatusNotFound,
+			wantError:  "was not found in Cerebro",
+		},
+		{
+			name:       "rate limited",
+			method:     http.MethodPost,
+			body:       `{"tool": "project_get_details", "arguments": {"project_permalink": "example-service"}}`,
+			failWith:   http.StatusTooManyRequests,
+			wantStatus: http.StatusTooManyRequests,
+			wantError:  "rate limiting",
+		},
+		{
+			name:       "upstream forbidden",
+			method:     http.MethodPost,
+			body:       `{"tool": "project_get_details", "arguments": {"project_permalink": "example-service"}}`,
+			failWith:   http.StatusForbidden,
+			wantStatus: http.StatusBadGateway,
+			wantError:  "HTTP 403",
+		},
+		{
+			name:       "upstream outage",
+			method:     http.MethodPost,
+			body:       `{"tool": "project_get_details", "arguments": {"project_permalink": "example-service"}}`,
+			failWith:   http.StatusInternalServerError,
+			wantStatus: http.StatusServiceUnavailable,
+			wantError:  "currently unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeCerebro(t)
+			fake.failWith(tt.failWith)
+			ps := newTestServer(fake)
+
+			req := httptest.NewRequest(tt.method, "/mcp", bytes.NewBufferString(tt.body))
+			rec := httptest.NewRecorder()
+			ps.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			var response struct {
+				Success bool             `json:"success"`
+				Data    *json.RawMessage `json:"data"`
+				Error   string           `json:"error"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("invalid JSON response %q: %v", rec.Body.String(), err)
+			}
+
+			if tt.wantError == "" {
+				if !response.Success || response.Data == nil {
+					t.Errorf("expected a successful response with data, got %s", rec.Body.String())
+				}
+				return
+			}
+			if response.Success || !strings.Contains(response.Error, tt.wantError) {
+				t.Errorf("error = %q, want it to contain %q", response.Error, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Dependency path search limits
+const (
+	DefaultPathMaxDepth = 6
+	MaxPathMaxDepth     = 10
+	MaxPaths            = 20
+	// MaxPathSearchSteps caps the dependencies followed while listing all paths
+	MaxPathSearchSteps = 200000
+)
+
+// DependencyPath is a chain of dependencies from one project to another
+type DependencyPath []ProjectDependency
+
+// optionalCount returns the number of optional dependencies in the path
+func (p DependencyPath) optionalCount() int {
+	count := 0
+	for _, dep := range p {
+		if dep.Optional {
+			count++
+		}
+	}
+	return count
+}
+
+// DependencyPaths is the result of a path search between two projects
+type DependencyPaths struct {
+	From Project
+	To   Project
+	// Shortest is the shortest path, nil if to is not reachable from from
+	Shortest DependencyPath
+	// ShortestRequired is the shortest path of required dependencies only, nil if none
+	ShortestRequired DependencyPath
+	// Reverse is set when no path exists but to depends on from instead
+	Reverse DependencyPath
+	// All lists every simple path up to MaxDepth hops when requested
+	All       []DependencyPath
+	MaxDepth  int
+	Truncated bool
+}
+
+// GetDependencyPath finds how fromPermalink depends on toPermalink. With allPaths it also
+// lists every simple path of at most maxDepth dependencies.
+func (s *ProjectService) GetDependencyPath(ctx context.Context, fromPermalink, toPermalink string, allPaths bool, maxDepth int) (*DependencyPathResult, error) {
+	from, err := s.findProjectForPath(ctx, "from", fromPermalink)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.findProjectForPath(ctx, "to", toPermalink)
+	if err != nil {
+		return nil, err
+	}
+	if from.ID == to.ID {
+		return nil, &ValidationError{Field: "to", Message: "must be a different project than from"}
+	}
+
+	catalog, err := s.source.Catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	graph := newCatalogGraph(catalog)
+
+	all := func(ProjectDependency) bool { return true }
+	required := func(dep ProjectDependency) bool { return !dep.Optional }
+
+	paths := &DependencyPaths{
+		From:     *from,
+		To:       *to,
+		Shortest: graph.pathEdges(graph.shortestPath(from.ID, to.ID, all), all),
+		MaxDepth: maxDepth,
+	}
+	if paths.Shortest.optionalCount() > 0 {
+		paths.ShortestRequired = graph.pathEdges(graph.shortestPath(from.ID, to.ID, required), required)
+	} else {
+		paths.ShortestRequired = paths.Shortest
+	}
+	if paths.Shortest == nil {
+		paths.Reverse = graph.pathEdges(graph.shortestPath(to.ID, from.ID, all), all)
+	}
+	if allPaths && paths.Shortest != nil {
+		paths.All, paths.Truncated = graph.simplePaths(from.ID, to.ID, maxDepth, MaxPaths)
+	}
+
+	return &DependencyPathResult{
+		Paths:         paths,
+		FormattedText: formatDependencyPaths(graph, paths, allPaths),
+	}, nil
+}
+
+// findProjectForPath looks up one end of a dependency path; deleted projects are rejected
+// because their dependencies are left out of the catalog graph
+func (s *ProjectService) findProjectForPath(ctx context.Context, field, permalink string) (*Project, error) {
+	if strings.TrimSpace(permalink) == "" {
+		return nil, &ValidationError{Field: field, Message: "cannot be empty"}
+	}
+
+	project, _, err := s.source.FindProject(ctx, permalink, false)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, s.projectNotFound(permalink)
+	}
+	if project.DeletedAt != nil {
+		return nil, &ValidationError{Field: field, Message: fmt.Sprintf("project %s was deleted on %s and has no dependencies in the catalog", permalink, *project.DeletedAt)}
+	}
+	return project, nil
+}
+
+// pathEdges returns the dependencies along a path of project IDs, preferring required
+// dependencies where two projects are linked more than once
+func (g *catalogGraph) pathEdges(ids []int, include func(ProjectDependency) bool) DependencyPath {
+	if ids == nil {
+		return nil
+	}
+
+	path := make(DependencyPath, 0, len(ids)-1)
+	for i := 0; i+1 < len(ids); i++ {
+		edge := -1
+		deps := g.edges[ids[i]]
+		for j, dep := range deps {
+			if dep.ProvidingProjectID == ids[i+1] && include(dep) && (edge == -1 || deps[edge].Optional) {
+				edge = j
+			}
+		}
+		path = append(path, deps[edge])
+	}
+	return path
+}
+
+// simplePaths returns the paths from one project to another that visit no project twice
+// and have at most maxDepth dependencies, shortest first and then with the fewest optional
+// dependencies. Paths are enumerated one length at a time, skipping projects that cannot
+// reach to within the remaining hops. The search stops after limit paths or
+// MaxPathSearchSteps dependencies followed and reports whether it did.
+func (g *catalogGraph) simplePaths(from, to, maxDepth, limit int) ([]DependencyPath, bool) {
+	distance := g.distancesTo(to)
+	shortest, ok := distance[from]
+	if !ok {
+		return nil, false
+	}
+
+	var paths, level []DependencyPath
+	truncated := false
+	steps := 0
+	visited := map[int]bool{from: true}
+	var current DependencyPath
+
+	// walk extends current by the dependencies of id into paths of exactly length hops,
+	// collecting them in level
+	var walk func(id, length int)
+	walk = func(id, length int) {
+		for _, dep := range g.edges[id] {
+			if truncated {
+				return
+			}
+			steps++
+			if steps > MaxPathSearchSteps {
+				truncated = true
+				return
+			}
+
+			// Paths end at to, so it only counts as the last hop
+			provider := dep.ProvidingProjectID
+			remaining := length - len(current) - 1
+			if d, ok := distance[provider]; visited[provider] || !ok || d > remaining || (provider == to && remaining > 0) {
+				continue
+			}
+
+			current = append(current, dep)
+			if provider == to {
+				if len(paths)+len(level) == limit {
+					truncated = true
+				} else {
+					level = append(level, append(DependencyPath(nil), current...))
+				}
+			} else {
+				visited[provider] = true
+				walk(provider, length)
+				visited[provider] = false
+			}
+			current = current[:len(current)-1]
+		}
+	}
+
+	for length := shortest; length <= maxDepth && !truncated; length++ {
+		level = nil
+		walk(from, length)
+		sortPaths(level)
+		paths = append(paths, level...)
+	}
+	return paths, truncated
+}
+
+// distancesTo returns the number of dependencies on the shortest path from every project
+// that can reach to, including to itself at 0
+func (g *catalogGraph) distancesTo(to int) map[int]int {
+	dependents := make(map[int][]int)
+	for id, deps := range g.edges {
+		for _, dep := range deps {
+			dependents[dep.ProvidingProjectID] = append(dependents[dep.ProvidingProjectID], id)
+		}
+	}
+
+	distance := map[int]int{to: 0}
+	queue := []int{to}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, dependent := range dependents[id] {
+			if _, seen := distance[dependent]; !seen {
+				distance[dependent] = distance[id] + 1
+				queue = append(queue, dependent)
+			}
+		}
+	}
+	return distance
+}
+
+// sortPaths orders paths by length, then by their number of optional dependencies
+func sortPaths(paths []DependencyPath) {
+	sort.SliceStable(paths, func(i, j int) bool {
+		if len(paths[i]) != len(paths[j]) {
+			return len(paths[i]) < len(paths[j])
+		}
+		return paths[i].optionalCount() < paths[j].optionalCount()
+	})
+}
+
+// formatDependencyPaths formats a dependency path search for display
+func formatDependencyPaths(graph *catalogGraph, paths *DependencyPaths, allPaths bool) string {
+	result := fmt.Sprintf("# Dependency Path: %s → %s\n\n", paths.From.Permalink, paths.To.Permalink)
+
+	if paths.Shortest == nil {
+		result += fmt.Sprintf("`%s` does not depend on `%s`, directly or transitively.\n", paths.From.Permalink, paths.To.Permalink)
+		if paths.Reverse != nil {
+			result += fmt.Sprintf("\n`%s` depends on `%s` instead:\n\n", paths.To.Permalink, paths.From.Permalink)
+			result += formatPathEdges(graph, paths.Reverse)
+		}
+		return result
+	}
+
+	result += fmt.Sprintf("## Shortest Path (%s)\n\n", pathSummary(paths.Shortest))
+	result += formatPathEdges(graph, paths.Shortest)
+
+	if paths.Shortest.optionalCount() > 0 {
+		if paths.ShortestRequired == nil {
+			result += fmt.Sprintf("\nEvery path includes an optional dependency, so `%s` should degrade gracefully when `%s` is down.\n", paths.From.Permalink, paths.To.Permalink)
+		} else {
+			result += fmt.Sprintf("\n## Shortest Required Path (%s)\n\n", pathSummary(paths.ShortestRequired))
+			result += formatPathEdges(graph, paths.ShortestRequired)
+		}
+	}
+
+	if allPaths {
+		result += fmt.Sprintf("\n## All Paths up to %d Hops (%d)\n\n", paths.MaxDepth, len(paths.All))
+		if len(paths.Shortest) > paths.MaxDepth {
+			result += fmt.Sprintf("The shortest path has %d hops, more than max_depth (%d), so no paths are listed.\n", len(paths.Shortest), paths.MaxDepth)
+		}
+		for i, path := range paths.All {
+			permalinks := []string{fmt.Sprintf("`%s`", paths.From.Permalink)}
+			for _, dep := range path {
+				permalinks = append(permalinks, fmt.Sprintf("`%s`", graph.permalink(dep.ProvidingProjectID)))
+			}
+			result += fmt.Sprintf("%d. %s (%s)\n", i+1, strings.Join(permalinks, " → "), pathSummary(path))
+		}
+		if paths.Truncated {
+			result += fmt.Sprintf("\nThe search stopped early, so only the shortest paths found are listed (at most %d); lower max_depth to narrow the search.\n", MaxPaths)
+		}
+	}
+
+	return result
+}
+
+// formatPathEdges formats the dependencies of a path as a numbered list
+func formatPathEdges(graph *catalogGraph, path DependencyPath) string {
+	result := ""
+	for i, dep := range path {
+		kind := "required"
+		if dep.Optional {
+			kind = "optional"
+		}
+		result += fmt.Sprintf("%d. `%s` → `%s` (%s)", i+1, graph.permalink(dep.DependentProjectID), graph.permalink(dep.ProvidingProjectID), kind)
+		if dep.Description != "" {
+			result += fmt.Sprintf(": %s", dep.Description)
+		}
+		result += "\n"
+	}
+	return result
+}
+
+// pathSummary describes the length of a path and how many optional dependencies it has
+func pathSummary(path DependencyPath) string {
+	hops := fmt.Sprintf("%d hops", len(path))
+	if len(path) == 1 {
+		hops = "1 hop"
+	}
+	if optional := path.optionalCount(); optional > 0 {
+		return fmt.Sprintf("%s, %d optional", hops, optional)
+	}
+	return hops + ", all required"
+}
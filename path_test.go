@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// pathSnapshot returns a catalog where web reaches db through api, through an optional
+// cache and through an optional search dependency of api, and reaches analytics only
+// through an optional dependency
+func pathSnapshot() *Snapshot {
+	dependency := func(id, dependent, provider int, optional bool, description string) ProjectDependency {
+		dep := testDependency(id, dependent, provider)
+		dep.Optional = optional
+		dep.Description = description
+		return dep
+	}
+
+	var projects []Project
+	for i, permalink := range []string{"web", "api", "db", "cache", "search", "analytics"} {
+		projects = append(projects, testProject(i+1, permalink, "Team Core", "Tier 1"))
+	}
+
+	return &Snapshot{
+		Projects: projects,
+		ProjectDependencies: []ProjectDependency{
+			dependency(1, 1, 2, false, "Serves every page"),
+			dependency(2, 2, 3, false, "Primary datastore"),
+			dependency(3, 1, 4, true, "Page fragment cache"),
+			dependency(4, 4, 3, false, ""),
+			dependency(5, 2, 5, true, "Search suggestions"),
+			dependency(6, 5, 3, false, "Index source"),
+			dependency(7, 1, 6, true, "Page view tracking"),
+		},
+	}
+}
+
+func TestGetDependencyPath(t *testing.T) {
+	service := NewProjectService(NewSnapshotSource(pathSnapshot()), NewValidator())
+	ctx := context.Background()
+
+	result, err := service.GetDependencyPath(ctx, "web", "db", true, DefaultPathMaxDepth)
+	if err != nil {
+		t.Fatalf("GetDependencyPath failed: %v", err)
+	}
+	paths := result.Paths
+	if len(paths.Shortest) != 2 || paths.Shortest.optionalCount() != 0 {
+		t.Errorf("shortest path = %+v, want the two required hops through api", paths.Shortest)
+	}
+	if len(paths.All) != 3 || len(paths.All[2]) != 3 {
+		t.Errorf("all paths = %d, want 3 with the longest last", len(paths.All))
+	}
+	assertGolden(t, "dependency_path", result.FormattedText)
+
+	limited, err := service.GetDependencyPath(ctx, "web", "db", true, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(limited.Paths.All) != 2 {
+		t.Errorf("paths up to 2 hops = %d, want 2", len(limited.Paths.All))
+	}
+
+	optional, err := service.GetDependencyPath(ctx, "web", "analytics", false, DefaultPathMaxDepth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if optional.Paths.ShortestRequired != nil || !strings.Contains(optional.FormattedText, "should degrade gracefully") {
+		t.Errorf("expected an optional-only path:\n%s", optional.FormattedText)
+	}
+
+	reverse, err := service.GetDependencyPath(ctx, "db", "web", false, DefaultPathMaxDepth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reverse.Paths.Shortest != nil || len(reverse.Paths.Reverse) != 2 {
+		t.Errorf("expected only a reverse path, got %+v", reverse.Paths)
+	}
+
+	_, err = service.GetDependencyPath(ctx, "web", "web", false, DefaultPathMaxDepth)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("expected ValidationError for the same project, got %v", err)
+	}
+	_, err = service.GetDependencyPath(ctx, "web", "nope", false, DefaultPathMaxDepth)
+	var notFoundErr *ProjectNotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Errorf("expected ProjectNotFoundError, got %v", err)
+	}
+}
+
+// layeredSnapshot returns a catalog where source depends on every project of the first of
+// layers layers of width projects, each project depends on every project of the next
+// layer and the last layer depends on sink. source also reaches sink through the shortcut
+// project, which has the highest ID so that a depth-first search meets it last.
+func layeredSnapshot(layers, width int) *Snapshot {
+	snapshot := &Snapshot{}
+	addProject := func(id int, permalink string) {
+		snapshot.Projects = append(snapshot.Projects, testProject(id, permalink, "Team Core", "Tier 1"))
+	}
+	addDependency := func(dependent, provider int) {
+		id := len(snapshot.ProjectDependencies) + 1
+		snapshot.ProjectDependencies = append(snapshot.ProjectDependencies, testDependency(id, dependent, provider))
+	}
+
+	const source, sink = 1, 2
+	shortcut := 3 + layers*width
+	addProject(source, "source")
+	addProject(sink, "sink")
+	addProject(shortcut, "shortcut")
+
+	layer := func(l, i int) int { return 3 + l*width + i }
+	for l := 0; l < layers; l++ {
+		for i := 0; i < width; i++ {
+			addProject(layer(l, i), fmt.Sprintf("layer-%d-%d", l, i))
+			switch {
+			case l == 0:
+				addDependency(source, layer(l, i))
+			default:
+				for j := 0; j < width; j++ {
+					addDependency(layer(l-1, j), layer(l, i))
+				}
+			}
+			if l == layers-1 {
+				addDependency(layer(l, i), sink)
+			}
+		}
+	}
+	addDependency(source, shortcut)
+	addDependency(shortcut, sink)
+	return snapshot
+}
+
+func TestDependencyPathDenseGraph(t *testing.T) {
+	service := NewProjectService(NewSnapshotSource(layeredSnapshot(9, 8)), NewValidator())
+
+	result, err := service.GetDependencyPath(context.Background(), "source", "sink", true, MaxPathMaxDepth)
+	if err != nil {
+		t.Fatalf("GetDependencyPath failed: %v", err)
+	}
+	paths := result.Paths
+	if !paths.Truncated || len(paths.All) != MaxPaths {
+		t.Fatalf("truncated = %t with %d paths, want true with %d", paths.Truncated, len(paths.All), MaxPaths)
+	}
+	if len(paths.All[0]) != 2 || paths.All[0][0].ProvidingProjectID != 3+9*8 {
+		t.Errorf("first path = %+v, want the two hops through the shortcut", paths.All[0])
+	}
+	for i := 1; i < len(paths.All); i++ {
+		if len(paths.All[i]) != 10 {
+			t.Fatalf("path %d has %d hops, want 10 through every layer", i+1, len(paths.All[i]))
+		}
+	}
+	if !strings.Contains(result.FormattedText, "The search stopped early") {
+		t.Errorf("expected a truncation note:\n%s", result.FormattedText)
+	}
+
+	// The layers are longer than max_depth, so only the shortcut is listed and nothing is cut
+	short, err := service.GetDependencyPath(context.Background(), "source", "sink", true, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if short.Paths.Truncated || len(short.Paths.All) != 1 {
+		t.Errorf("truncated = %t with %d paths, want only the shortcut", short.Paths.Truncated, len(short.Paths.All))
+	}
+}
+
+func TestDependencyPathSearchStepLimit(t *testing.T) {
+	graph := newCatalogGraph(layeredSnapshot(9, 8))
+
+	// Without a path limit the search is bounded by MaxPathSearchSteps alone
+	paths, truncated := graph.simplePaths(1, 2, MaxPathMaxDepth, 1<<30)
+	if !truncated {
+		t.Fatal("expected the step limit to stop the search")
+	}
+	if len(paths) == 0 || len(paths[0]) != 2 {
+		t.Errorf("expected the shortcut before any layered path, got %d paths", len(paths))
+	}
+}
+
+func TestDependencyPathEdgeCases(t *testing.T) {
+	snapshot := pathSnapshot()
+	// web → api → db is cut by deleting api; web still reaches db through the optional cache
+	snapshot.Projects[1].DeletedAt = deletedAt()
+	snapshot.Projects = append(snapshot.Projects, testProject(7, "island", "Team Core", "Tier 1"))
+	service := NewProjectService(NewSnapshotSource(snapshot), NewValidator())
+	ctx := context.Background()
+
+	result, err := service.GetDependencyPath(ctx, "web", "db", true, DefaultPathMaxDepth)
+	if err != nil {
+		t.Fatalf("GetDependencyPath failed: %v", err)
+	}
+	if len(result.Paths.All) != 1 || result.Paths.All[0][0].ProvidingProjectID != 4 {
+		t.Errorf("expected only the path through cache once api is deleted, got %+v", result.Paths.All)
+	}
+	if result.Paths.ShortestRequired != nil {
+		t.Errorf("expected no required path without api, got %+v", result.Paths.ShortestRequired)
+	}
+
+	tooShort, err := service.GetDependencyPath(ctx, "web", "db", true, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tooShort.Paths.All) != 0 || tooShort.Paths.Truncated {
+		t.Errorf("expected no paths within 1 hop, got %+v", tooShort.Paths.All)
+	}
+	if !strings.Contains(tooShort.FormattedText, "The shortest path has 2 hops, more than max_depth (1), so no paths are listed.") {
+		t.Errorf("expected a note that the shortest path exceeds max_depth:\n%s", tooShort.FormattedText)
+	}
+
+	// A deleted end is rejected rather than reported as not depending on the other
+	for _, ends := range [][2]string{{"api", "db"}, {"web", "api"}} {
+		_, err := service.GetDependencyPath(ctx, ends[0], ends[1], false, DefaultPathMaxDepth)
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || !strings.Contains(validationErr.Message, "was deleted") {
+			t.Errorf("%s → %s: expected a ValidationError for the deleted api, got %v", ends[0], ends[1], err)
+		}
+	}
+
+	unreachable, err := service.GetDependencyPath(ctx, "web", "island", true, DefaultPathMaxDepth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unreachable.Paths.Shortest != nil || unreachable.Paths.Reverse != nil || len(unreachable.Paths.All) != 0 {
+		t.Errorf("expected no path to an isolated project, got %+v", unreachable.Paths)
+	}
+	if !strings.Contains(unreachable.FormattedText, "does not depend on `island`") {
+		t.Errorf("unexpected report:\n%s", unreachable.FormattedText)
+	}
+}
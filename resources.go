@@ -0,0 +1,372 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Resource usage report limits
+const (
+	DefaultResourceTop = 10
+	MaxResourceTop     = 100
+)
+
+// Resource usage groupings of the catalog-wide report
+const (
+	ResourceGroupByProject  = "project"
+	ResourceGroupByTeam     = "team"
+	ResourceGroupByCategory = "category"
+)
+
+// Resource usage metrics to rank by
+const (
+	ResourceSortCPU    = "cpu"
+	ResourceSortMemory = "memory"
+)
+
+// ResourceUsage is the CPU and memory usage recorded in Cerebro. HasCPU and HasMemory are
+// false when a value is missing or not a number.
+type ResourceUsage struct {
+	CPU       float64
+	Memory    float64
+	HasCPU    bool
+	HasMemory bool
+}
+
+// add accumulates another usage into u
+func (u *ResourceUsage) add(other ResourceUsage) {
+	if other.HasCPU {
+		u.CPU += other.CPU
+		u.HasCPU = true
+	}
+	if other.HasMemory {
+		u.Memory += other.Memory
+		u.HasMemory = true
+	}
+}
+
+// metric returns the usage ranked by sortBy and whether it is known
+func (u ResourceUsage) metric(sortBy string) (float64, bool) {
+	if sortBy == ResourceSortMemory {
+		return u.Memory, u.HasMemory
+	}
+	return u.CPU, u.HasCPU
+}
+
+// projectResourceUsage parses the usage fields of a project
+func projectResourceUsage(project Project) ResourceUsage {
+	var usage ResourceUsage
+	usage.CPU, usage.HasCPU = parseUsage(project.CPUUsage)
+	usage.Memory, usage.HasMemory = parseUsage(project.MemoryUsage)
+	return usage
+}
+
+// parseUsage parses a usage value such as "26397.38" or "1,024". ok is false for empty,
+// negative and non-numeric values.
+func parseUsage(value string) (usage float64, ok bool) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), ",", "")
+	if value == "" {
+		return 0, false
+	}
+	usage, err := strconv.ParseFloat(value, 64)
+	if err != nil || usage < 0 {
+		return 0, false
+	}
+	return usage, true
+}
+
+// ResourceUsageRow is a project, team or category in a resource usage report
+type ResourceUsageRow struct {
+	Name      string
+	Permalink string
+	Projects  int
+	Usage     ResourceUsage
+	// Optional marks optional dependencies in a project report
+	Optional bool
+	// Error says why a dependency has no usage data, if its provider could not be found
+	Error string
+}
+
+// ResourceUsageReport ranks resource usage. With Project set it covers the project and the
+// providers of its direct dependencies; otherwise it ranks the top consumers of the catalog.
+type ResourceUsageReport struct {
+	Project *Project
+	SortBy  string
+	GroupBy string
+	Top     int
+	// Rows are ranked by SortBy, rows without data last
+	Rows []ResourceUsageRow
+	// Total sums the usage of every project in the report, not only the rows shown
+	Total ResourceUsage
+	// Unparsed counts projects without usable usage data
+	Unparsed int
+}
+
+// GetResourceUsage reports the resource usage of a project and the providers of its direct
+// dependencies, or of the top consumers of the catalog grouped by groupBy when permalink
+// is empty. An empty groupBy or sortBy and a top below 1 select the defaults.
+func (s *ProjectService) GetResourceUsage(ctx context.Context, permalink, groupBy, sortBy string, top int) (*ResourceUsageResult, error) {
+	if groupBy == "" {
+		groupBy = ResourceGroupByProject
+	}
+	if sortBy == "" {
+		sortBy = ResourceSortCPU
+	}
+	if top < 1 {
+		top = DefaultResourceTop
+	}
+	if err := s.validator.ValidateEnumList("group_by", []string{groupBy}, ResourceGroupByProject, ResourceGroupByTeam, ResourceGroupByCategory); err != nil {
+		return nil, err
+	}
+	if err := s.validator.ValidateEnumList("sort_by", []string{sortBy}, ResourceSortCPU, ResourceSortMemory); err != nil {
+		return nil, err
+	}
+
+	var report *ResourceUsageReport
+	var err error
+	if permalink != "" {
+		report, err = s.projectResourceUsage(ctx, permalink, sortBy)
+	} else {
+		report, err = s.catalogResourceUsage(ctx, groupBy, sortBy, top)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResourceUsageResult{
+		Report:        report,
+		FormattedText: formatResourceUsage(report),
+	}, nil
+}
+
+// projectResourceUsage collects the usage of a project and of its direct providers
+func (s *ProjectService) projectResourceUsage(ctx context.Context, permalink, sortBy string) (*ResourceUsageReport, error) {
+	if err := s.validator.ValidateProjectPermalink(permalink); err != nil {
+		return nil, err
+	}
+
+	found, dependencies, err := s.source.FindProject(ctx, permalink, true)
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, s.projectNotFound(permalink)
+	}
+	project := *found
+
+	var live []ProjectDependency
+	for _, dep := range s.filterDependencies(dependencies, project.ID) {
+		if dep.DeletedAt == nil {
+			live = append(live, dep)
+		}
+	}
+
+	report := &ResourceUsageReport{Project: &project, SortBy: sortBy}
+	seen := make(map[int]bool)
+	for _, res := range s.fetchDependenciesAsync(ctx, live) {
+		if seen[res.dep.ProvidingProjectID] {
+			continue
+		}
+		seen[res.dep.ProvidingProjectID] = true
+
+		row := ResourceUsageRow{Name: fmt.Sprintf("Project ID %d", res.dep.ProvidingProjectID), Projects: 1, Optional: res.dep.Optional}
+		switch {
+		case res.err != nil:
+			row.Error = "could not be fetched"
+		case res.providingProject == nil:
+			row.Error = "not found in Cerebro"
+		default:
+			row.Name = res.providingProject.Name
+			row.Permalink = res.providingProject.Permalink
+			row.Usage = projectResourceUsage(*res.providingProject)
+		}
+		if !row.Usage.HasCPU && !row.Usage.HasMemory {
+			report.Unparsed++
+		}
+		report.Total.add(row.Usage)
+		report.Rows = append(report.Rows, row)
+	}
+
+	sortResourceRows(report.Rows, sortBy)
+	return report, nil
+}
+
+// catalogResourceUsage ranks the projects, teams or categories of the catalog by usage
+func (s *ProjectService) catalogResourceUsage(ctx context.Context, groupBy, sortBy string, top int) (*ResourceUsageReport, error) {
+	catalog, err := s.source.Catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	graph := newCatalogGraph(catalog)
+
+	report := &ResourceUsageReport{SortBy: sortBy, GroupBy: groupBy, Top: top}
+	rows := make(map[string]*ResourceUsageRow)
+	var order []string
+	for _, id := range graph.ids {
+		project := graph.projects[id]
+		usage := projectResourceUsage(project)
+		if !usage.HasCPU && !usage.HasMemory {
+			report.Unparsed++
+			continue
+		}
+		report.Total.add(usage)
+
+		key, row := strconv.Itoa(project.ID), ResourceUsageRow{Name: project.Name, Permalink: project.Permalink}
+		switch groupBy {
+		case ResourceGroupByTeam:
+			key = project.ProjectStakeholderOwner
+			if key == "" {
+				key = noOwnerTeam
+			}
+			row = ResourceUsageRow{Name: key}
+		case ResourceGroupByCategory:
+			key = valueOrNone(project.Category)
+			row = ResourceUsageRow{Name: key}
+		}
+		if _, ok := rows[key]; !ok {
+			rows[key] = &row
+			order = append(order, key)
+		}
+		rows[key].Projects++
+		rows[key].Usage.add(usage)
+	}
+
+	for _, key := range order {
+		report.Rows = append(report.Rows, *rows[key])
+	}
+	sortResourceRows(report.Rows, sortBy)
+	if len(report.Rows) > top {
+		report.Rows = report.Rows[:top]
+	}
+	return report, nil
+}
+
+// sortResourceRows orders rows by the sortBy metric, highest first, then by name. Rows
+// without the metric come last.
+func sortResourceRows(rows []ResourceUsageRow, sortBy string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		vi, iKnown := rows[i].Usage.metric(sortBy)
+		vj, jKnown := rows[j].Usage.metric(sortBy)
+		if iKnown != jKnown {
+			return iKnown
+		}
+		if vi != vj {
+			return vi > vj
+		}
+		return rows[i].Name < rows[j].Name
+	})
+}
+
+// formatUsage formats a usage value, or a dash when it is unknown
+func formatUsage(value float64, ok bool) string {
+	if !ok {
+		return "-"
+	}
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+// formatRawUsage formats a usage field of a project, keeping values that are not numbers
+// as they are
+func formatRawUsage(value string) string {
+	if usage, ok := parseUsage(value); ok {
+		return formatUsage(usage, true)
+	}
+	return valueOrNone(value)
+}
+
+// formatUsageShare formats value as a percentage of total
+func formatUsageShare(value float64, ok bool, total float64) string {
+	if !ok || total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", value/total*100)
+}
+
+// formatResourceUsage formats a resource usage report as markdown tables
+func formatResourceUsage(report *ResourceUsageReport) string {
+	metric := "CPU"
+	if report.SortBy == ResourceSortMemory {
+		metric = "memory"
+	}
+
+	if report.Project != nil {
+		return formatProjectResourceUsage(report, metric)
+	}
+
+	// Projects are the rows unless they are grouped, as in catalogResourceUsage
+	name := "Project"
+	switch report.GroupBy {
+	case ResourceGroupByTeam:
+		name = "Team"
+	case ResourceGroupByCategory:
+		name = "Category"
+	}
+	result := fmt.Sprintf("# Top Resource Consumers by %s\n\n", name)
+	result += fmt.Sprintf("The top %d ranked by %s usage. Catalog total: CPU %s, memory %s.", report.Top, metric,
+		formatUsage(report.Total.CPU, report.Total.HasCPU), formatUsage(report.Total.Memory, report.Total.HasMemory))
+	if report.Unparsed > 0 {
+		result += fmt.Sprintf(" Projects without usage data: %d.", report.Unparsed)
+	}
+	result += "\n\n"
+
+	if len(report.Rows) == 0 {
+		return result + "No projects have usage data.\n"
+	}
+
+	result += fmt.Sprintf("| # | %s | Projects | CPU | CPU Share | Memory | Memory Share |\n", name)
+	result += "| --- | --- | --- | --- | --- | --- | --- |\n"
+	for i, row := range report.Rows {
+		label := tableCell(row.Name)
+		if row.Permalink != "" {
+			label += fmt.Sprintf(" (`%s`)", row.Permalink)
+		}
+		result += fmt.Sprintf("| %d | %s | %d | %s | %s | %s | %s |\n", i+1, label, row.Projects,
+			formatUsage(row.Usage.CPU, row.Usage.HasCPU), formatUsageShare(row.Usage.CPU, row.Usage.HasCPU, report.Total.CPU),
+			formatUsage(row.Usage.Memory, row.Usage.HasMemory), formatUsageShare(row.Usage.Memory, row.Usage.HasMemory, report.Total.Memory))
+	}
+	return result
+}
+
+// formatProjectResourceUsage formats the usage of a project and its direct providers
+func formatProjectResourceUsage(report *ResourceUsageReport, metric string) string {
+	project := *report.Project
+	usage := projectResourceUsage(project)
+
+	result := fmt.Sprintf("# Resource Usage for: %s\n\n", project.Name)
+	result += fmt.Sprintf("**CPU Usage:** %s\n", formatUsage(usage.CPU, usage.HasCPU))
+	result += fmt.Sprintf("**Memory Usage:** %s\n\n", formatUsage(usage.Memory, usage.HasMemory))
+
+	result += fmt.Sprintf("## Direct Dependencies (%d)\n\n", len(report.Rows))
+	if len(report.Rows) == 0 {
+		return result + "None.\n"
+	}
+
+	result += fmt.Sprintf("Ranked by %s usage. Total of the providers: CPU %s, memory %s.", metric,
+		formatUsage(report.Total.CPU, report.Total.HasCPU), formatUsage(report.Total.Memory, report.Total.HasMemory))
+	if report.Unparsed > 0 {
+		result += fmt.Sprintf(" Providers without usage data: %d.", report.Unparsed)
+	}
+	result += "\n\n"
+
+	result += "| # | Project | Dependency | CPU | Memory |\n"
+	result += "| --- | --- | --- | --- | --- |\n"
+	for i, row := range report.Rows {
+		label := tableCell(row.Name)
+		if row.Permalink != "" {
+			label += fmt.Sprintf(" (`%s`)", row.Permalink)
+		}
+		if row.Error != "" {
+			label += fmt.Sprintf(" (%s)", row.Error)
+		}
+		kind := "required"
+		if row.Optional {
+			kind = "optional"
+		}
+		result += fmt.Sprintf("| %d | %s | %s | %s | %s |\n", i+1, label, kind,
+			formatUsage(row.Usage.CPU, row.Usage.HasCPU), formatUsage(row.Usage.Memory, row.Usage.HasMemory))
+	}
+	return result
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// resourceSnapshot returns a small catalog with usage data in several shapes
+func resourceSnapshot() *Snapshot {
+	project := func(id int, permalink, owner, category, cpu, memory string) Project {
+		p := testProject(id, permalink, owner, "Tier 1")
+		p.Category = category
+		p.CPUUsage = cpu
+		p.MemoryUsage = memory
+		return p
+	}
+	gone := project(6, "gone", "Team Payments", "Service", "9999", "9999")
+	gone.DeletedAt = deletedAt()
+
+	return &Snapshot{
+		Projects: []Project{
+			project(1, "checkout", "Team Payments", "Service", "120.5", "2,048"),
+			project(2, "payments", "Team Payments", "Service", "300", "1024"),
+			project(3, "search", "Team Discovery", "Service", "80.25", ""),
+			project(4, "reports", "", "Library", "n/a", "512"),
+			project(5, "scratch", "Team Discovery", "Tool", "", ""),
+			gone,
+		},
+		ProjectDependencies: []ProjectDependency{
+			testDependency(11, 1, 2),
+			testDependency(12, 1, 3),
+			testDependency(13, 1, 99),
+		},
+	}
+}
+
+func TestParseUsage(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+		ok    bool
+	}{
+		{"26397.38", 26397.38, true},
+		{" 1,024 ", 1024, true},
+		{"0", 0, true},
+		{"", 0, false},
+		{"n/a", 0, false},
+		{"-5", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseUsage(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseUsage(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGetResourceUsageCatalog(t *testing.T) {
+	service := NewProjectService(NewSnapshotSource(resourceSnapshot()), NewValidator())
+
+	result, err := service.GetResourceUsage(context.Background(), "", ResourceGroupByProject, ResourceSortCPU, 2)
+	if err != nil {
+		t.Fatalf("GetResourceUsage failed: %v", err)
+	}
+	report := result.Report
+	if len(report.Rows) != 2 || report.Rows[0].Permalink != "payments" || report.Rows[1].Permalink != "checkout" {
+		t.Errorf("top 2 by CPU = %+v, want payments then checkout", report.Rows)
+	}
+	if report.Total.CPU != 500.75 || report.Total.Memory != 3584 {
+		t.Errorf("total = %+v, want CPU 500.75 and memory 3584 without the deleted project", report.Total)
+	}
+	if report.Unparsed != 1 {
+		t.Errorf("unparsed = %d, want 1 (scratch)", report.Unparsed)
+	}
+
+	result, err = service.GetResourceUsage(context.Background(), "", ResourceGroupByTeam, ResourceSortMemory, DefaultResourceTop)
+	if err != nil {
+		t.Fatalf("GetResourceUsage failed: %v", err)
+	}
+	assertGolden(t, "resource_usage_by_team", result.FormattedText)
+}
+
+func TestGetResourceUsageProject(t *testing.T) {
+	service := NewProjectService(NewSnapshotSource(resourceSnapshot()), NewValidator())
+
+	result, err := service.GetResourceUsage(context.Background(), "checkout", ResourceGroupByProject, ResourceSortCPU, DefaultResourceTop)
+	if err != nil {
+		t.Fatalf("GetResourceUsage failed: %v", err)
+	}
+	assertGolden(t, "resource_usage_project", result.FormattedText)
+
+	if !strings.Contains(result.FormattedText, "Project ID 99 (not found in Cerebro)") {
+		t.Errorf("expected missing providers to be listed")
+	}
+
+	_, err = service.GetResourceUsage(context.Background(), "nope", ResourceGroupByProject, ResourceSortCPU, DefaultResourceTop)
+	var notFound *ProjectNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("expected ProjectNotFoundError, got %v", err)
+	}
+}
+
+func TestGetResourceUsageDefaults(t *testing.T) {
+	service := NewProjectService(NewSnapshotSource(resourceSnapshot()), NewValidator())
+
+	result, err := service.GetResourceUsage(context.Background(), "", "", "", 0)
+	if err != nil {
+		t.Fatalf("GetResourceUsage with empty arguments failed: %v", err)
+	}
+	report := result.Report
+	if report.GroupBy != ResourceGroupByProject || report.SortBy != ResourceSortCPU || report.Top != DefaultResourceTop {
+		t.Errorf("defaults = %q, %q, %d; want project, cpu, %d", report.GroupBy, report.SortBy, report.Top, DefaultResourceTop)
+	}
+	if !strings.HasPrefix(result.FormattedText, "# Top Resource Consumers by Project") {
+		t.Errorf("unexpected report:\n%s", result.FormattedText)
+	}
+
+	for _, args := range [][2]string{{"color", ""}, {"", "disk"}} {
+		_, err := service.GetResourceUsage(context.Background(), "", args[0], args[1], DefaultResourceTop)
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("GetResourceUsage(%q, %q): expected ValidationError, got %v", args[0], args[1], err)
+		}
+	}
+}
+
+func TestGetResourceUsageEdgeCases(t *testing.T) {
+	empty := NewProjectService(NewSnapshotSource(&Snapshot{}), NewValidator())
+	result, err := empty.GetResourceUsage(context.Background(), "", ResourceGroupByTeam, ResourceSortMemory, DefaultResourceTop)
+	if err != nil {
+		t.Fatalf("GetResourceUsage on an empty catalog failed: %v", err)
+	}
+	if len(result.Report.Rows) != 0 || !strings.Contains(result.FormattedText, "No projects have usage data.") {
+		t.Errorf("expected an empty report, got:\n%s", result.FormattedText)
+	}
+
+	// search has no dependencies; its own usage is still reported
+	service := NewProjectService(NewSnapshotSource(resourceSnapshot()), NewValidator())
+	leaf, err := service.GetResourceUsage(context.Background(), "search", "", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leaf.Report.Rows) != 0 || !strings.Contains(leaf.FormattedText, "**CPU Usage:** 80.25") || !strings.Contains(leaf.FormattedText, "## Direct Dependencies (0)\n\nNone.") {
+		t.Errorf("unexpected report for a project without dependencies:\n%s", leaf.FormattedText)
+	}
+}
+
+func TestFormatResourceUsageWithoutGroupBy(t *testing.T) {
+	text := formatResourceUsage(&ResourceUsageReport{Top: DefaultResourceTop})
+	if !strings.Contains(text, "# Top Resource Consumers by Project") {
+		t.Errorf("expected projects as the default rows:\n%s", text)
+	}
+}
@@ -3,95 +3,233 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 )
 
 // ProjectService handles project-related business logic
 type ProjectService struct {
-	client    *CerebroClient
-	validator *Validator
+	source           ProjectSource
+	validator        *Validator
+	maxConcurrency   int
+	snapshotDir      string
+	maxResponseChars int
+}
+
+// ServiceOption configures optional ProjectService behavior
+type ServiceOption func(*ProjectService)
+
+// WithMaxConcurrency limits the number of concurrent Cerebro requests per call
+func WithMaxConcurrency(n int) ServiceOption {
+	return func(s *ProjectService) {
+		s.maxConcurrency = n
+	}
+}
+
+// WithSnapshotDir sets the directory of snapshots that catalog diffs can compare
+func WithSnapshotDir(dir string) ServiceOption {
+	return func(s *ProjectService) {
+		s.snapshotDir = dir
+	}
+}
+
+// WithResponseBudget limits formatted tool output to about chars characters; 0 disables
+// the limit
+func WithResponseBudget(chars int) ServiceOption {
+	return func(s *ProjectService) {
+		s.maxResponseChars = chars
+	}
 }
 
 // NewProjectService creates a new ProjectService
-func NewProjectService(client *CerebroClient, validator *Validator) *ProjectService {
-	return &ProjectService{
-		client:    client,
-		validator: validator,
+func NewProjectService(source ProjectSource, validator *Validator, opts ...ServiceOption) *ProjectService {
+	service := &ProjectService{
+		source:           source,
+		validator:        validator,
+		maxConcurrency:   DefaultMaxConcurrency,
+		maxResponseChars: DefaultMaxResponseChars,
+	}
+	for _, opt := range opts {
+		opt(service)
 	}
+	return service
 }
 
-// GetProjectDetails retrieves detailed information about a project
-func (s *ProjectService) GetProjectDetails(ctx context.Context, permalink string) (*ProjectDetailsResult, error) {
+// GetProjectDetails retrieves detailed information about a project. fields selects the
+// sections of the formatted text; none selects every section.
+func (s *ProjectService) GetProjectDetails(ctx context.Context, permalink string, fields []string) (*ProjectDetailsResult, error) {
 	if err := s.validator.ValidateProjectPermalink(permalink); err != nil {
 		return nil, err
 	}
-
-	params := CerebroAPIParameters{
-		searchKey:   "permalink",
-		searchValue: permalink,
-		inlines:     "project_repository_urls,project_stakeholder_owner_name,project_stakeholder_oncall_name,link_deployment_url,link_deployment_urls",
+	if err := s.validator.ValidateEnumList("fields", fields, detailsFields...); err != nil {
+		return nil, err
 	}
 
-	apiURL := s.client.buildURL(params)
-	response, err := s.client.makeRequest(ctx, apiURL)
+	project, _, err := s.source.FindProject(ctx, permalink, false)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(response.Projects) == 0 {
-		return nil, &ProjectNotFoundError{Permalink: permalink}
+	if project == nil {
+		return nil, s.projectNotFound(permalink)
 	}
 
-	project := response.Projects[0]
-
 	return &ProjectDetailsResult{
-		Project:       project,
-		FormattedText: s.formatProjectDetails(project, permalink),
+		Project:       *project,
+		FormattedText: s.formatProjectDetails(*project, permalink, fields),
 	}, nil
 }
 
 // GetProjectDependencies retrieves dependency information for a project
-func (s *ProjectService) GetProjectDependencies(ctx context.Context, permalink string) (*ProjectDependenciesResult, error) {
+func (s *ProjectService) GetProjectDependencies(ctx context.Context, permalink string, opts DependencyOptions) (*ProjectDependenciesResult, error) {
 	if err := s.validator.ValidateProjectPermalink(permalink); err != nil {
 		return nil, err
 	}
 
-	params := CerebroAPIParameters{
-		searchKey:   "permalink",
-		searchValue: permalink,
-		inlines:     "project_repository_urls,project_stakeholder_owner_name,project_stakeholder_oncall_name,link_deployment_url,link_deployment_urls",
-		includes:    "dependent_project_dependencies",
-	}
-
-	apiURL := s.client.buildURL(params)
-	response, err := s.client.makeRequest(ctx, apiURL)
+	found, dependencies, err := s.source.FindProject(ctx, permalink, true)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(response.Projects) == 0 {
-		return nil, &ProjectNotFoundError{Permalink: permalink}
+	if found == nil {
+		return nil, s.projectNotFound(permalink)
 	}
 
-	project := response.Projects[0]
-	if len(response.ProjectDependencies) == 0 {
+	project := *found
+	if len(dependencies) == 0 {
 		return &ProjectDependenciesResult{
 			Project:             project,
 			ProjectDependencies: []ProjectDependency{},
+			Dependencies:        []DependencyDetail{},
 			FormattedText:       fmt.Sprintf("# No dependencies found for project: %s\n\n", project.Name),
 		}, nil
 	}
 
-	relevantDependencies := s.filterDependencies(response.ProjectDependencies, project.ID)
-	dependenciesWithDetails := s.fetchDependenciesAsync(ctx, relevantDependencies)
+	var selected []ProjectDependency
+	var hidden hiddenDependencies
+	for _, dep := range s.filterDependencies(dependencies, project.ID) {
+		switch {
+		case dep.DeletedAt != nil && !opts.IncludeDeleted:
+			hidden.deleted++
+		case !opts.includes(dep):
+			hidden.filtered++
+		default:
+			selected = append(selected, dep)
+		}
+	}
+
+	// List dependencies in display order, which groups them when requested
+	var ordered []dependencyResult
+	for _, group := range groupDependencyResults(s.fetchDependenciesAsync(ctx, selected), opts.GroupBy) {
+		ordered = append(ordered, group.results...)
+	}
+	start, end, err := pageRange(opts.Cursor, opts.cursorQuery(project), opts.Limit, len(ordered))
+	if err != nil {
+		return nil, err
+	}
+	text, page := fitDependencyPage(project, ordered, opts, hidden, start, end, s.maxResponseChars)
+
+	shown := ordered[page.start:page.end]
+	relevantDependencies := make([]ProjectDependency, len(shown))
+	for i, res := range shown {
+		relevantDependencies[i] = res.dep
+	}
 
-	return &ProjectDependenciesResult{
+	result := &ProjectDependenciesResult{
 		Project:             project,
 		ProjectDependencies: relevantDependencies,
-		FormattedText:       s.formatDependencies(project, dependenciesWithDetails),
+		Dependencies:        dependencyDetails(shown),
+		FormattedText:       text,
+	}
+	if page.end < len(ordered) {
+		result.NextCursor = encodeCursor(opts.cursorQuery(project), page.end)
+	}
+	return result, nil
+}
+
+// GetCatalogDiff compares two snapshots from the snapshot directory. When since is set,
+// it reports the changes recorded in the to snapshot after that time instead. An empty
+// to selects the newest snapshot.
+func (s *ProjectService) GetCatalogDiff(ctx context.Context, from, to, since string) (*CatalogDiffResult, error) {
+	if s.snapshotDir == "" {
+		return nil, fmt.Errorf("catalog diffs need a snapshot directory; set snapshot.dir, CEREBRO_SNAPSHOT_DIR or -snapshot-dir")
+	}
+	if from == "" && since == "" {
+		return nil, &ValidationError{Field: "from", Message: "either from or since is required"}
+	}
+	if from != "" && since != "" {
+		return nil, &ValidationError{Field: "since", Message: "cannot be combined with from"}
+	}
+
+	if to == "" {
+		names, err := snapshotNames(s.snapshotDir)
+		if err != nil {
+			return nil, err
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("snapshot directory %s contains no snapshots", s.snapshotDir)
+		}
+		to = names[len(names)-1]
+	}
+	toSnapshot, err := s.loadNamedSnapshot("to", to)
+	if err != nil {
+		return nil, err
+	}
+
+	var diff *CatalogDiff
+	if since != "" {
+		sinceTime, err := parseSince(since)
+		if err != nil {
+			return nil, &ValidationError{Field: "since", Message: "must be an RFC 3339 timestamp or a YYYY-MM-DD date"}
+		}
+		diff = DiffSince(to, toSnapshot, sinceTime)
+	} else {
+		fromSnapshot, err := s.loadNamedSnapshot("from", from)
+		if err != nil {
+			return nil, err
+		}
+		diff = DiffSnapshots(from, fromSnapshot, to, toSnapshot)
+	}
+
+	return &CatalogDiffResult{
+		Diff:          diff,
+		FormattedText: formatCatalogDiff(diff),
 	}, nil
 }
 
+// loadNamedSnapshot loads a snapshot by its file name in the snapshot directory
+func (s *ProjectService) loadNamedSnapshot(field, name string) (*Snapshot, error) {
+	if name != filepath.Base(name) || name == ".." || name == "." {
+		return nil, &ValidationError{Field: field, Message: "must be a snapshot file name without a directory"}
+	}
+	path := filepath.Join(s.snapshotDir, name)
+	if _, err := os.Stat(path); err != nil {
+		return nil, &ValidationError{Field: field, Message: fmt.Sprintf("snapshot %q does not exist", name)}
+	}
+	return LoadSnapshot(path)
+}
+
+// dependencyDetails converts fetched dependency results into their exported form
+func dependencyDetails(results []dependencyResult) []DependencyDetail {
+	details := make([]DependencyDetail, len(results))
+	for i, res := range results {
+		details[i] = DependencyDetail{
+			Dependency:       res.dep,
+			ProvidingProject: res.providingProject,
+		}
+		if res.err != nil {
+			details[i].Error = res.err.Error()
+		}
+	}
+	return details
+}
+
+// projectNotFound returns the error for a permalink that the data source does not have
+func (s *ProjectService) projectNotFound(permalink string) error {
+	return &ProjectNotFoundError{Permalink: permalink, Source: s.source.Name()}
+}
+
 // filterDependencies filters dependencies where the project is the dependent
 func (s *ProjectService) filterDependencies(deps []ProjectDependency, projectID int) []ProjectDependency {
 	var relevant []ProjectDependency
@@ -115,180 +253,25 @@ type dependencyResult struct {
 func (s *ProjectService) fetchDependenciesAsync(ctx context.Context, dependencies []ProjectDependency) []dependencyResult {
 	results := make([]dependencyResult, len(dependencies))
 	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, max(s.maxConcurrency, 1))
 
 	for i, dep := range dependencies {
 		wg.Add(1)
 		go func(index int, dependency ProjectDependency) {
 			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
 
-			params := CerebroAPIParameters{
-				searchKey:   "id",
-				searchValue: fmt.Sprintf("%d", dependency.ProvidingProjectID),
-			}
-
-			apiURL := s.client.buildURL(params)
-			response, err := s.client.makeRequest(ctx, apiURL)
-
+			providingProject, err := s.source.FindProjectByID(ctx, dependency.ProvidingProjectID)
 			if err != nil {
 				results[index] = dependencyResult{index: index, dep: dependency, err: err}
 				return
 			}
 
-			if len(response.Projects) == 0 {
-				results[index] = dependencyResult{index: index, dep: dependency, providingProject: nil, err: nil}
-				return
-			}
-
-			results[index] = dependencyResult{index: index, dep: dependency, providingProject: &response.Projects[0], err: nil}
+			results[index] = dependencyResult{index: index, dep: dependency, providingProject: providingProject, err: nil}
 		}(i, dep)
 	}
 
 	wg.Wait()
 	return results
 }
-
-// formatProjectDetails formats project details for display
-func (s *ProjectService) formatProjectDetails(project Project, permalink string) string {
-	result := fmt.Sprintf("# Project Details for: %s\n\n", permalink)
-
-	result += fmt.Sprintf("**Project Name:** %s\n", project.Name)
-	result += fmt.Sprintf("**Description:** %s\n", project.Description)
-	result += fmt.Sprintf("**Category:** %s\n", project.Category)
-
-	// Use CalculatedCriticalityTier if available, otherwise use CriticalityTier
-	if project.CalculatedCriticalityTier == "Unknown" {
-		result += fmt.Sprintf("**Criticality Tier:** %s\n", project.CriticalityTier)
-	} else {
-		result += fmt.Sprintf("**Criticality Tier:** %s\n", project.CalculatedCriticalityTier)
-	}
-
-	result += fmt.Sprintf("**Release State:** %s\n", project.ReleaseState)
-	result += fmt.Sprintf("**Owner:** %s\n", project.ProjectStakeholderOwner)
-	result += fmt.Sprintf("**Slack Channel:** %s\n", project.SlackChannel)
-
-	if len(project.ProjectRepositoryURLs) == 0 {
-		result += "No project repository URLs found.\n"
-	} else {
-		result += fmt.Sprintf("\n**Project Repository URLs (%d):**\n", len(project.ProjectRepositoryURLs))
-		for i, repoURL := range project.ProjectRepositoryURLs {
-			result += fmt.Sprintf("%d. %s\n", i+1, repoURL)
-		}
-	}
-
-	// Collect all deployment URLs and remove duplicates
-	var allDeploymentUrls []string
-	urlSet := make(map[string]bool)
-
-	// Add primary deployment URL
-	if project.PrimaryDeploymentUrl != "" && !urlSet[project.PrimaryDeploymentUrl] {
-		allDeploymentUrls = append(allDeploymentUrls, project.PrimaryDeploymentUrl)
-		urlSet[project.PrimaryDeploymentUrl] = true
-	}
-
-	// Add additional deployment URLs
-	for _, depURL := range project.AdditionalDeploymentUrls {
-		if depURL != "" && !urlSet[depURL] {
-			allDeploymentUrls = append(allDeploymentUrls, depURL)
-			urlSet[depURL] = true
-		}
-	}
-
-	if len(allDeploymentUrls) == 0 {
-		result += "No deployment URLs found.\n"
-	} else {
-		result += fmt.Sprintf("\n**Project Deployment URLs (%d):**\n", len(allDeploymentUrls))
-		for i, depURL := range allDeploymentUrls {
-			result += fmt.Sprintf("%d. %s\n", i+1, depURL)
-		}
-	}
-
-	return result
-}
-
-// formatDependencies formats dependencies for display
-func (s *ProjectService) formatDependencies(project Project, dependencyResults []dependencyResult) string {
-	result := fmt.Sprintf("# Dependencies for Project: %s\n\n", project.Name)
-	result += fmt.Sprintf("**Project ID:** %d\n", project.ID)
-	result += fmt.Sprintf("**Permalink:** %s\n", project.Permalink)
-	result += fmt.Sprintf("**Description:** %s\n\n", project.Description)
-	result += fmt.Sprintf("## Dependencies (%d)\n\n", len(dependencyResults))
-
-	for i, res := range dependencyResults {
-		if res.err != nil {
-			result += fmt.Sprintf("### %d. Error fetching project ID %d\n", i+1, res.dep.ProvidingProjectID)
-			result += fmt.Sprintf("- **Error:** %v\n\n", res.err)
-			continue
-		}
-
-		if res.providingProject == nil {
-			result += fmt.Sprintf("### %d. Project ID %d (Not Found)\n", i+1, res.dep.ProvidingProjectID)
-			result += fmt.Sprintf("- **Dependency ID:** %d\n", res.dep.ID)
-			result += fmt.Sprintf("- **Optional:** %t\n\n", res.dep.Optional)
-			continue
-		}
-
-		providingProject := res.providingProject
-		result += fmt.Sprintf("### %d. %s\n", i+1, providingProject.Name)
-		result += fmt.Sprintf("- **Dependency ID:** %d\n", res.dep.ID)
-		result += fmt.Sprintf("- **Providing Project ID:** %d\n", res.dep.ProvidingProjectID)
-		result += fmt.Sprintf("- **Permalink:** %s\n", providingProject.Permalink)
-		result += fmt.Sprintf("- **Description:** %s\n", providingProject.Description)
-		result += fmt.Sprintf("- **Category:** %s\n", providingProject.Category)
-
-		// Use CalculatedCriticalityTier if available, otherwise use CriticalityTier
-		if providingProject.CalculatedCriticalityTier == "Unknown" {
-			result += fmt.Sprintf("**Criticality Tier:** %s\n", providingProject.CriticalityTier)
-		} else {
-			result += fmt.Sprintf("**Criticality Tier:** %s\n", providingProject.CalculatedCriticalityTier)
-		}
-
-		result += fmt.Sprintf("- **Release State:** %s\n", providingProject.ReleaseState)
-		result += fmt.Sprintf("- **Owner Team:** %s\n", providingProject.ProjectStakeholderOwner)
-		result += fmt.Sprintf("- **Slack Channel:** %s\n", providingProject.SlackChannel)
-
-		if len(providingProject.ProjectRepositoryURLs) == 0 {
-			result += "No project repository URLs found.\n"
-		} else {
-			result += fmt.Sprintf("\n**Project Repository URLs (%d):**\n", len(providingProject.ProjectRepositoryURLs))
-			for i, repoURL := range providingProject.ProjectRepositoryURLs {
-				result += fmt.Sprintf("%d. %s\n", i+1, repoURL)
-			}
-		}
-
-		// Collect all deployment URLs and remove duplicates
-		var allDeploymentUrls []string
-		urlSet := make(map[string]bool)
-
-		// Add primary deployment URL
-		if providingProject.PrimaryDeploymentUrl != "" && !urlSet[providingProject.PrimaryDeploymentUrl] {
-			allDeploymentUrls = append(allDeploymentUrls, providingProject.PrimaryDeploymentUrl)
-			urlSet[providingProject.PrimaryDeploymentUrl] = true
-		}
-
-		// Add additional deployment URLs
-		for _, depURL := range providingProject.AdditionalDeploymentUrls {
-			if depURL != "" && !urlSet[depURL] {
-				allDeploymentUrls = append(allDeploymentUrls, depURL)
-				urlSet[depURL] = true
-			}
-		}
-
-		if len(allDeploymentUrls) == 0 {
-			result += "No deployment URLs found.\n"
-		} else {
-			result += fmt.Sprintf("\n**Project Deployment URLs (%d):**\n", len(allDeploymentUrls))
-			for i, depURL := range allDeploymentUrls {
-				result += fmt.Sprintf("%d. %s\n", i+1, depURL)
-			}
-		}
-
-		result += fmt.Sprintf("- **Optional Dependency:** %t\n", res.dep.Optional)
-
-		if res.dep.Description != "" {
-			result += fmt.Sprintf("- **Dependency Description:** %s\n", res.dep.Description)
-		}
-		result += "\n"
-	}
-
-	return result
-}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGetProjectDetails(t *testing.T) {
+	fake := newFakeCerebro(t)
+	service := newTestService(fake)
+
+	result, err := service.GetProjectDetails(context.Background(), "example-service", nil)
+	if err != nil {
+		t.Fatalf("GetProjectDetails failed: %v", err)
+	}
+
+	if result.Project.ID != 9 {
+		t.Errorf("project ID = %d, want 9", result.Project.ID)
+	}
+	for _, want := range []string{
+		"# Project Details for: example-service",
+		"**Project Name:** Example Service",
+		"**Criticality Tier:** Tier 1",
+		"**Owner:** ",
+		"https://github.com/example/example-service-main",
+	} {
+		if !strings.Contains(result.FormattedText, want) {
+			t.Errorf("formatted text missing %q:\n%s", want, result.FormattedText)
+		}
+	}
+}
+
+func TestGetProjectDetailsErrors(t *testing.T) {
+	fake := newFakeCerebro(t)
+	service := newTestService(fake)
+
+	_, err := service.GetProjectDetails(context.Background(), "  ", nil)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("expected ValidationError for a blank permalink, got %v", err)
+	}
+
+	_, err = service.GetProjectDetails(context.Background(), "does-not-exist", nil)
+	var notFoundErr *ProjectNotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Errorf("expected ProjectNotFoundError, got %v", err)
+	}
+}
+
+func TestGetProjectDependencies(t *testing.T) {
+	fake := newFakeCerebro(t)
+	fake.addProject(fakeProvider(8, "Auth Service"))
+	fake.addProject(fakeProvider(10, "Database Service"))
+	service := newTestService(fake)
+
+	result, err := service.GetProjectDependencies(context.Background(), "example-service", DependencyOptions{})
+	if err != nil {
+		t.Fatalf("GetProjectDependencies failed: %v", err)
+	}
+
+	if got := len(result.ProjectDependencies); got != 83 {
+		t.Fatalf("dependency count = %d, want 83", got)
+	}
+	if len(result.Dependencies) != len(result.ProjectDependencies) {
+		t.Fatalf("expected a detail for every dependency")
+	}
+
+	// Results keep the order of the dependency list
+	for i, detail := range result.Dependencies {
+		if detail.Dependency.ID != result.ProjectDependencies[i].ID {
+			t.Fatalf("dependency %d out of order", i)
+		}
+	}
+
+	first := result.Dependencies[0]
+	if first.ProvidingProject == nil || first.ProvidingProject.Name != "Auth Service" {
+		t.Errorf("expected the first provider to be Auth Service, got %+v", first.ProvidingProject)
+	}
+	if result.Dependencies[2].ProvidingProject != nil {
+		t.Errorf("expected providers missing from Cerebro to be nil")
+	}
+
+	for _, want := range []string{
+		"## Dependencies (83)",
+		"### 1. Auth Service",
+		"### 2. Database Service",
+		"### 3. Project ID 14 (Not Found)",
+	} {
+		if !strings.Contains(result.FormattedText, want) {
+			t.Errorf("formatted text missing %q", want)
+		}
+	}
+}
+
+func TestGetProjectDependenciesNoDependencies(t *testing.T) {
+	fake := newFakeCerebro(t)
+	fake.addProject(fakeProvider(500, "Leaf Service"))
+	service := newTestService(fake)
+
+	result, err := service.GetProjectDependencies(context.Background(), "leaf-service", DependencyOptions{})
+	if err != nil {
+		t.Fatalf("GetProjectDependencies failed: %v", err)
+	}
+	if len(result.ProjectDependencies) != 0 {
+		t.Errorf("expected no dependencies, got %d", len(result.ProjectDependencies))
+	}
+	if !strings.HasPrefix(result.FormattedText, "# No dependencies found for project: Leaf Service") {
+		t.Errorf("unexpected formatted text: %q", result.FormattedText)
+	}
+}
+
+func TestGetProjectDependenciesProviderErrors(t *testing.T) {
+	fake := newFakeCerebro(t)
+	service := newTestService(fake)
+
+	// Let the project lookup succeed, then fail every provider lookup
+	client := NewCerebroClient(fake.URL(), StaticToken(fakeCerebroToken))
+	response, err := client.makeRequest(context.Background(), client.buildURL(CerebroAPIParameters{
+		searchKey:   "permalink",
+		searchValue: "example-service",
+		includes:    "dependent_project_dependencies",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.failWith(503)
+
+	deps := service.filterDependencies(response.ProjectDependencies, response.Projects[0].ID)
+	results := service.fetchDependenciesAsync(context.Background(), deps[:3])
+	for _, res := range results {
+		if res.err == nil {
+			t.Errorf("expected an error for dependency %d", res.dep.ID)
+		}
+	}
+
+	text := service.formatDependencies(response.Projects[0], results, DependencyOptions{}, hiddenDependencies{})
+	if !strings.Contains(text, "### 1. Error fetching project ID 8") {
+		t.Errorf("expected per-dependency errors in output:\n%s", text)
+	}
+}
+
+// deletedDependenciesSnapshot returns a project with a live, a soft-deleted and a dangling
+// dependency, and one on a deleted provider
+func deletedDependenciesSnapshot() *Snapshot {
+	oldBilling := testProject(3, "old-billing", "Team Billing", "Tier 2")
+	oldBilling.DeletedAt = deletedAt()
+	removed := testDependency(12, 1, 2)
+	removed.DeletedAt = deletedAt()
+
+	return &Snapshot{
+		Projects: []Project{
+			testProject(1, "checkout", "Team Payments", "Tier 1"),
+			testProject(2, "payments", "Team Payments", "Tier 1"),
+			oldBilling,
+		},
+		ProjectDependencies: []ProjectDependency{
+			testDependency(11, 1, 2),
+			removed,
+			testDependency(13, 1, 3),
+			testDependency(14, 1, 99),
+		},
+	}
+}
+
+func TestGetProjectDependenciesDeleted(t *testing.T) {
+	service := NewProjectService(NewSnapshotSource(deletedDependenciesSnapshot()), NewValidator())
+
+	result, err := service.GetProjectDependencies(context.Background(), "checkout", DependencyOptions{})
+	if err != nil {
+		t.Fatalf("GetProjectDependencies failed: %v", err)
+	}
+	if got := len(result.Dependencies); got != 3 {
+		t.Fatalf("dependency count = %d, want 3 without the soft-deleted one", got)
+	}
+	for _, want := range []string{
+		"Soft-deleted dependencies hidden: 1",
+		"## Dependencies (3)",
+		"### 2. Old-billing (Provider Deleted)",
+		"- **Provider Deleted At:** " + testDeletedAt,
+		"### 3. Project ID 99 (Not Found)",
+	} {
+		if !strings.Contains(result.FormattedText, want) {
+			t.Errorf("formatted text missing %q:\n%s", want, result.FormattedText)
+		}
+	}
+	if strings.Contains(result.FormattedText, "Dependency Deleted") {
+		t.Errorf("expected soft-deleted dependencies to be hidden by default")
+	}
+
+	result, err = service.GetProjectDependencies(context.Background(), "checkout", DependencyOptions{IncludeDeleted: true})
+	if err != nil {
+		t.Fatalf("GetProjectDependencies failed: %v", err)
+	}
+	if got := len(result.Dependencies); got != 4 {
+		t.Fatalf("dependency count = %d, want 4 with the soft-deleted one", got)
+	}
+	for _, want := range []string{
+		"### 2. Payments (Dependency Deleted)",
+		"- **Dependency Deleted At:** " + testDeletedAt,
+	} {
+		if !strings.Contains(result.FormattedText, want) {
+			t.Errorf("formatted text missing %q:\n%s", want, result.FormattedText)
+		}
+	}
+	if strings.Contains(result.FormattedText, "hidden") {
+		t.Errorf("expected no hidden note when deleted dependencies are included")
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SnapshotVersion is the format version written by WriteSnapshot
+const SnapshotVersion = 1
+
+// Snapshot is an offline copy of Cerebro project data. Raw Cerebro API responses
+// load as snapshots with version 0 and no fetch time.
+type Snapshot struct {
+	Version             int                 `json:"version"`
+	FetchedAt           time.Time           `json:"fetched_at"`
+	Projects            []Project           `json:"projects"`
+	ProjectDependencies []ProjectDependency `json:"project_dependencies"`
+	Repositories        []Repository        `json:"repositories"`
+}
+
+// SnapshotSource serves project data from a Snapshot instead of the Cerebro API
+type SnapshotSource struct {
+	name         string
+	snapshot     *Snapshot
+	byID         map[int]*Project
+	byPermalink  map[string]*Project
+	dependencies map[int][]ProjectDependency
+}
+
+// LoadSnapshot reads a snapshot from an exported snapshot, a Cerebro JSON response file,
+// or a directory of them; gzip-compressed files are supported. Files are read in lexical
+// order; when the same project, dependency or repository appears more than once the
+// first occurrence wins.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+		}
+		files = nil
+		for _, entry := range entries {
+			if !entry.IsDir() && isSnapshotFile(entry.Name()) {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+		sort.Strings(files)
+		if len(files) == 0 {
+			return nil, fmt.Errorf("snapshot directory %s contains no .json files", path)
+		}
+	}
+
+	snapshot := &Snapshot{}
+	for _, file := range files {
+		var part Snapshot
+		if err := readJSONFile(file, &part); err != nil {
+			return nil, fmt.Errorf("failed to read snapshot file %s: %w", file, err)
+		}
+		if part.Version > SnapshotVersion {
+			return nil, fmt.Errorf("snapshot file %s has version %d, this server supports up to %d", file, part.Version, SnapshotVersion)
+		}
+		if snapshot.FetchedAt.IsZero() {
+			snapshot.FetchedAt = part.FetchedAt
+		}
+
+		snapshot.Projects = append(snapshot.Projects, part.Projects...)
+		snapshot.ProjectDependencies = append(snapshot.ProjectDependencies, part.ProjectDependencies...)
+		snapshot.Repositories = append(snapshot.Repositories, part.Repositories...)
+	}
+
+	snapshot.dedupe()
+	snapshot.Version = SnapshotVersion
+	return snapshot, nil
+}
+
+// snapshotNames returns the names of the snapshot files in dir in lexical order, which is
+// chronological for date-stamped names such as cerebro-2025-01-31.json.gz
+func snapshotNames(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && isSnapshotFile(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// isSnapshotFile reports whether name has a snapshot file extension
+func isSnapshotFile(name string) bool {
+	return strings.HasSuffix(name, ".json") || strings.HasSuffix(name, ".json.gz")
+}
+
+// dedupe removes repeated projects, dependencies and repositories, keeping the first
+func (s *Snapshot) dedupe() {
+	s.Projects = dedupeByID(s.Projects, func(p Project) int { return p.ID })
+	s.ProjectDependencies = dedupeByID(s.ProjectDependencies, func(d ProjectDependency) int { return d.ID })
+	s.Repositories = dedupeByID(s.Repositories, func(r Repository) int { return r.ID })
+}
+
+// dedupeByID returns items without repeated IDs, keeping the first occurrence
+func dedupeByID[T any](items []T, id func(T) int) []T {
+	seen := make(map[int]bool)
+	var result []T
+	for _, item := range items {
+		if !seen[id(item)] {
+			seen[id(item)] = true
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// WriteSnapshot writes snapshot to path as gzip-compressed JSON, replacing any existing
+// file only once the new one is complete
+func WriteSnapshot(path string, snapshot *Snapshot) error {
+	snapshot.Version = SnapshotVersion
+	return writeJSONFile(path, snapshot)
+}
+
+// readJSONFile decodes a JSON file, transparently decompressing gzip content
+func readJSONFile(path string, v interface{}) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var r io.Reader = reader
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	return json.NewDecoder(r).Decode(v)
+}
+
+// writeJSONFile atomically writes v to path as gzip-compressed JSON
+func writeJSONFile(path string, v interface{}) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	gz := gzip.NewWriter(tmp)
+	if err := json.NewEncoder(gz).Encode(v); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// SnapshotSourceOption configures optional SnapshotSource behavior
+type SnapshotSourceOption func(*SnapshotSource)
+
+// WithSourceName sets how messages refer to the snapshot, e.g. "snapshot catalog.json.gz"
+func WithSourceName(name string) SnapshotSourceOption {
+	return func(s *SnapshotSource) {
+		s.name = name
+	}
+}
+
+// NewSnapshotSource creates a ProjectSource that serves data from snapshot
+func NewSnapshotSource(snapshot *Snapshot, opts ...SnapshotSourceOption) *SnapshotSource {
+	s := &SnapshotSource{
+		name:         "the snapshot",
+		snapshot:     snapshot,
+		byID:         make(map[int]*Project),
+		byPermalink:  make(map[string]*Project),
+		dependencies: make(map[int][]ProjectDependency),
+	}
+
+	for i := range snapshot.Projects {
+		project := &snapshot.Projects[i]
+		s.byID[project.ID] = project
+		if _, ok := s.byPermalink[project.Permalink]; !ok {
+			s.byPermalink[project.Permalink] = project
+		}
+	}
+	for _, dep := range snapshot.ProjectDependencies {
+		s.dependencies[dep.DependentProjectID] = append(s.dependencies[dep.DependentProjectID], dep)
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *SnapshotSource) Name() string {
+	return s.name
+}
+
+func (s *SnapshotSource) FindProject(ctx context.Context, permalink string, withDependencies bool) (*Project, []ProjectDependency, error) {
+	project, ok := s.byPermalink[permalink]
+	if !ok {
+		return nil, nil, nil
+	}
+
+	if !withDependencies {
+		return project, nil, nil
+	}
+	return project, s.dependencies[project.ID], nil
+}
+
+func (s *SnapshotSource) FindProjectByID(ctx context.Context, id int) (*Project, error) {
+	return s.byID[id], nil
+}
+
+func (s *SnapshotSource) FindDependencies(ctx context.Context, projectID int) ([]ProjectDependency, error) {
+	return s.dependencies[projectID], nil
+}
+
+func (s *SnapshotSource) Catalog(ctx context.Context) (*Snapshot, error) {
+	return s.snapshot, nil
+}
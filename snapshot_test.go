@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadSnapshotDirectory(t *testing.T) {
+	snapshot, err := LoadSnapshot(filepath.Join("examples", "cerebro-api"))
+	if err != nil {
+		t.Fatalf("LoadSnapshot failed: %v", err)
+	}
+
+	if got := len(snapshot.Projects); got != 2 {
+		t.Errorf("project count = %d, want 2", got)
+	}
+	if got := len(snapshot.ProjectDependencies); got != 83 {
+		t.Errorf("dependency count = %d, want 83", got)
+	}
+}
+
+func TestLoadSnapshotErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadSnapshot(dir); err == nil {
+		t.Error("expected an error for a directory without JSON files")
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSnapshot(bad); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+
+	if _, err := LoadSnapshot(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestProjectServiceWithSnapshotSource(t *testing.T) {
+	snapshot, err := LoadSnapshot(filepath.Join("examples", "cerebro-api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := NewProjectService(NewSnapshotSource(snapshot), NewValidator())
+
+	details, err := service.GetProjectDetails(context.Background(), "example-service", nil)
+	if err != nil {
+		t.Fatalf("GetProjectDetails failed: %v", err)
+	}
+	if details.Project.ID != 9 {
+		t.Errorf("expected the first project with the permalink to win, got ID %d", details.Project.ID)
+	}
+
+	deps, err := service.GetProjectDependencies(context.Background(), "example-service", DependencyOptions{})
+	if err != nil {
+		t.Fatalf("GetProjectDependencies failed: %v", err)
+	}
+	if got := len(deps.Dependencies); got != 83 {
+		t.Fatalf("dependency count = %d, want 83", got)
+	}
+	if !strings.Contains(deps.FormattedText, "### 1. Project ID 8 (Not Found)") {
+		t.Errorf("expected providers missing from the snapshot to be reported as not found")
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// projectInlines are the inline fields requested for every project lookup
+const projectInlines = "project_repository_urls,project_stakeholder_owner_name,project_stakeholder_oncall_name,link_deployment_url,link_deployment_urls"
+
+// ProjectSource provides Cerebro project data to the ProjectService
+type ProjectSource interface {
+	// FindProject returns the project with the given permalink and, when
+	// withDependencies is set, its dependencies. A nil project means it does not exist.
+	FindProject(ctx context.Context, permalink string, withDependencies bool) (*Project, []ProjectDependency, error)
+	// FindProjectByID returns the project with the given ID, or nil if it does not exist
+	FindProjectByID(ctx context.Context, id int) (*Project, error)
+	// FindDependencies returns the dependencies of the project with the given ID
+	FindDependencies(ctx context.Context, projectID int) ([]ProjectDependency, error)
+	// Name describes where the data comes from, e.g. "Cerebro" or "snapshot catalog.json.gz"
+	Name() string
+	// Catalog returns every project in Cerebro with the dependencies they declare and
+	// their repositories. The snapshot may be shared between calls and must not be modified.
+	Catalog(ctx context.Context) (*Snapshot, error)
+}
+
+// liveSource reads project data from the Cerebro API
+type liveSource struct {
+	client     *CerebroClient
+	catalogTTL time.Duration
+	interval   time.Duration
+
+	// mu guards the cached catalog and makes concurrent Catalog calls share one fetch
+	mu               sync.Mutex
+	catalog          *Snapshot
+	catalogExpiresAt time.Time
+}
+
+// LiveSourceOption configures optional liveSource behavior
+type LiveSourceOption func(*liveSource)
+
+// WithCatalogTTL keeps the assembled catalog for ttl so that catalog-wide tools do not
+// page through Cerebro on every call; 0 fetches it every time
+func WithCatalogTTL(ttl time.Duration) LiveSourceOption {
+	return func(s *liveSource) {
+		s.catalogTTL = ttl
+	}
+}
+
+// WithCatalogRate makes at most rate page requests per second while fetching the catalog
+func WithCatalogRate(rate float64) LiveSourceOption {
+	return func(s *liveSource) {
+		s.interval = pageInterval(rate)
+	}
+}
+
+// NewLiveSource creates a ProjectSource backed by the Cerebro API. The catalog is cached
+// for DefaultCatalogTTL and its pages are requested at DefaultExportRate.
+func NewLiveSource(client *CerebroClient, opts ...LiveSourceOption) ProjectSource {
+	source := &liveSource{
+		client:     client,
+		catalogTTL: DefaultCatalogTTL,
+		interval:   pageInterval(DefaultExportRate),
+	}
+	for _, opt := range opts {
+		opt(source)
+	}
+	return source
+}
+
+func (s *liveSource) Name() string {
+	return "Cerebro"
+}
+
+func (s *liveSource) FindProject(ctx context.Context, permalink string, withDependencies bool) (*Project, []ProjectDependency, error) {
+	params := CerebroAPIParameters{
+		searchKey:   "permalink",
+		searchValue: permalink,
+		inlines:     projectInlines,
+	}
+	if withDependencies {
+		params.includes = "dependent_project_dependencies"
+	}
+
+	response, err := s.client.makeRequest(ctx, s.client.buildURL(params))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(response.Projects) == 0 {
+		return nil, nil, nil
+	}
+
+	return &response.Projects[0], response.ProjectDependencies, nil
+}
+
+func (s *liveSource) FindProjectByID(ctx context.Context, id int) (*Project, error) {
+	params := CerebroAPIParameters{
+		searchKey:   "id",
+		searchValue: fmt.Sprintf("%d", id),
+		inlines:     projectInlines,
+	}
+
+	response, err := s.client.makeRequest(ctx, s.client.buildURL(params))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(response.Projects) == 0 {
+		return nil, nil
+	}
+
+	return &response.Projects[0], nil
+}
+
+func (s *liveSource) FindDependencies(ctx context.Context, projectID int) ([]ProjectDependency, error) {
+	params := CerebroAPIParameters{
+		searchKey:   "id",
+		searchValue: fmt.Sprintf("%d", projectID),
+		includes:    "dependent_project_dependencies",
+	}
+
+	response, err := s.client.makeRequest(ctx, s.client.buildURL(params))
+	if err != nil {
+		return nil, err
+	}
+
+	var dependencies []ProjectDependency
+	for _, dep := range response.ProjectDependencies {
+		if dep.DependentProjectID == projectID {
+			dependencies = append(dependencies, dep)
+		}
+	}
+	return dependencies, nil
+}
+
+func (s *liveSource) Catalog(ctx context.Context) (*Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.catalog != nil && time.Now().Before(s.catalogExpiresAt) {
+		return s.catalog, nil
+	}
+
+	catalog, err := s.fetchCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.catalogTTL > 0 {
+		s.catalog, s.catalogExpiresAt = catalog, time.Now().Add(s.catalogTTL)
+	}
+	return catalog, nil
+}
+
+// fetchCatalog pages through the whole catalog, pacing the page requests like an export
+func (s *liveSource) fetchCatalog(ctx context.Context) (*Snapshot, error) {
+	catalog := &Snapshot{Version: SnapshotVersion, FetchedAt: time.Now().UTC()}
+	pager := newCatalogPager(nil, 0)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for page := 1; ; page++ {
+		params := CerebroAPIParameters{
+			inlines:  projectInlines,
+			includes: "dependent_project_dependencies,repositories",
+			page:     page,
+			perPage:  DefaultExportPageSize,
+		}
+
+		response, err := s.client.makeRequest(ctx, s.client.buildURL(params))
+		if err != nil {
+			return nil, err
+		}
+
+		last, err := pager.next(page, response)
+		if err != nil {
+			return nil, err
+		}
+
+		catalog.Projects = append(catalog.Projects, response.Projects...)
+		catalog.ProjectDependencies = append(catalog.ProjectDependencies, response.ProjectDependencies...)
+		catalog.Repositories = append(catalog.Repositories, response.Repositories...)
+		if last {
+			break
+		}
+		if err := waitForPage(ctx, ticker); err != nil {
+			return nil, err
+		}
+	}
+
+	catalog.dedupe()
+	return catalog, nil
+}
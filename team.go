@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TeamProjects are the projects a team owns or is on call for
+type TeamProjects struct {
+	Team   string
+	Owned  []Project
+	Oncall []Project
+	// Similar lists team names containing the query when no project matched
+	Similar []string
+}
+
+// GetTeamProjects lists the projects whose owner or on-call stakeholder is team,
+// ignoring case
+func (s *ProjectService) GetTeamProjects(ctx context.Context, team string) (*TeamProjectsResult, error) {
+	if strings.TrimSpace(team) == "" {
+		return nil, &ValidationError{Field: "team", Message: "cannot be empty"}
+	}
+
+	catalog, err := s.source.Catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := findTeamProjects(newCatalogGraph(catalog), strings.TrimSpace(team))
+	return &TeamProjectsResult{
+		Projects:      projects,
+		FormattedText: formatTeamProjects(projects),
+	}, nil
+}
+
+// findTeamProjects collects the projects of a team from the catalog graph
+func findTeamProjects(graph *catalogGraph, team string) *TeamProjects {
+	result := &TeamProjects{Team: team}
+	similar := make(map[string]bool)
+	query := strings.ToLower(team)
+
+	for _, id := range graph.ids {
+		project := graph.projects[id]
+		switch {
+		case strings.EqualFold(project.ProjectStakeholderOwner, team):
+			result.Owned = append(result.Owned, project)
+		case strings.EqualFold(project.ProjectStakeholderOncall, team):
+			result.Oncall = append(result.Oncall, project)
+		}
+
+		for _, name := range []string{project.ProjectStakeholderOwner, project.ProjectStakeholderOncall} {
+			if name != "" && strings.Contains(strings.ToLower(name), query) {
+				similar[name] = true
+			}
+		}
+	}
+
+	if len(result.Owned) == 0 && len(result.Oncall) == 0 {
+		for name := range similar {
+			result.Similar = append(result.Similar, name)
+		}
+		sort.Strings(result.Similar)
+	}
+
+	sortByTier(result.Owned)
+	sortByTier(result.Oncall)
+	return result
+}
+
+// sortByTier orders projects by criticality tier, most critical first, then by permalink
+func sortByTier(projects []Project) {
+	sort.SliceStable(projects, func(i, j int) bool {
+		ti, tj := effectiveCriticalityTier(projects[i]), effectiveCriticalityTier(projects[j])
+		if ti.Less(tj) || tj.Less(ti) {
+			return ti.Less(tj)
+		}
+		return projects[i].Permalink < projects[j].Permalink
+	})
+}
+
+// repositoryCount returns the number of repositories linked to a project
+func repositoryCount(project Project) int {
+	if len(project.RepositoriesIDs) > 0 {
+		return len(project.RepositoriesIDs)
+	}
+	return len(project.ProjectRepositoryURLs)
+}
+
+// formatTeamProjects formats the projects of a team as markdown tables
+func formatTeamProjects(projects *TeamProjects) string {
+	result := fmt.Sprintf("# Projects for Team: %s\n\n", projects.Team)
+
+	if len(projects.Owned) == 0 && len(projects.Oncall) == 0 {
+		result += fmt.Sprintf("No projects are owned by or on call with %q.\n", projects.Team)
+		if len(projects.Similar) > 0 {
+			result += "\nSimilar team names:\n"
+			for _, name := range projects.Similar {
+				result += fmt.Sprintf("- %s\n", name)
+			}
+		}
+		return result
+	}
+
+	result += fmt.Sprintf("## Owned (%d)\n", len(projects.Owned))
+	result += formatProjectTable(projects.Owned)
+
+	result += fmt.Sprintf("\n## On Call Only (%d)\n", len(projects.Oncall))
+	result += formatProjectTable(projects.Oncall)
+
+	return result
+}
+
+// formatProjectTable formats projects as a markdown table
+func formatProjectTable(projects []Project) string {
+	if len(projects) == 0 {
+		return "None.\n"
+	}
+
+	result := "| Project | Permalink | Tier | Release State | Slack Channel | Repos |\n"
+	result += "| --- | --- | --- | --- | --- | --- |\n"
+	for _, project := range projects {
+		result += fmt.Sprintf("| %s | `%s` | %s | %s | %s | %d |\n",
+			tableCell(project.Name), project.Permalink, tableCell(effectiveCriticalityTier(project).String()),
+			tableCell(project.ReleaseState.String()), slackChannel(project.SlackChannel), repositoryCount(project))
+	}
+	return result
+}
+
+// tableCell escapes a value for use in a markdown table cell
+func tableCell(value string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(value)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGetTeamProjects(t *testing.T) {
+	project := func(id int, permalink, owner, oncall, tier string) Project {
+		p := testProject(id, permalink, owner, tier)
+		p.ProjectStakeholderOncall = oncall
+		p.ReleaseState = "GA"
+		p.SlackChannel = permalink
+		p.RepositoriesIDs = []int{id * 10, id*10 + 1}
+		return p
+	}
+	snapshot := &Snapshot{
+		Projects: []Project{
+			project(1, "ledger", "Team Payments", "Payments On-Call", "Tier 2"),
+			project(2, "payments-api", "team payments", "Payments On-Call", "Tier 0"),
+			project(3, "fraud-check", "Team Risk", "Team Payments", "Tier 1"),
+			project(4, "reporting", "Team Data", "Team Data", "Tier 3"),
+		},
+	}
+	service := NewProjectService(NewSnapshotSource(snapshot), NewValidator())
+
+	result, err := service.GetTeamProjects(context.Background(), "Team Payments")
+	if err != nil {
+		t.Fatalf("GetTeamProjects failed: %v", err)
+	}
+	if len(result.Projects.Owned) != 2 || result.Projects.Owned[0].Permalink != "payments-api" {
+		t.Errorf("owned = %+v, want payments-api first", result.Projects.Owned)
+	}
+	if len(result.Projects.Oncall) != 1 || result.Projects.Oncall[0].Permalink != "fraud-check" {
+		t.Errorf("on call = %+v", result.Projects.Oncall)
+	}
+	assertGolden(t, "team_projects", result.FormattedText)
+
+	none, err := service.GetTeamProjects(context.Background(), "payments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(none.Projects.Similar) != 3 {
+		t.Errorf("similar teams = %v, want 3", none.Projects.Similar)
+	}
+
+	_, err = service.GetTeamProjects(context.Background(), " ")
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("expected ValidationError for a blank team, got %v", err)
+	}
+}
+
+func TestTeamProjectsOrderingAndDuplicates(t *testing.T) {
+	retired := testProject(3, "old-billing", "Team Payments", "Tier 0")
+	retired.DeletedAt = deletedAt()
+	both := testProject(2, "ledger", "Team Payments", "Tier 10")
+	both.ProjectStakeholderOncall = "TEAM PAYMENTS"
+	snapshot := &Snapshot{
+		Projects: []Project{
+			testProject(1, "payments-api", "Team Payments", "Tier 2"),
+			both,
+			retired,
+			testProject(4, "unknown-tier", "Team Payments", ""),
+		},
+	}
+	service := NewProjectService(NewSnapshotSource(snapshot), NewValidator())
+
+	result, err := service.GetTeamProjects(context.Background(), "  team payments ")
+	if err != nil {
+		t.Fatalf("GetTeamProjects failed: %v", err)
+	}
+	owned := result.Projects.Owned
+	// The deleted project is left out, Tier 10 sorts after Tier 2 and unknown tiers come last
+	if len(owned) != 3 || owned[0].Permalink != "payments-api" || owned[1].Permalink != "ledger" || owned[2].Permalink != "unknown-tier" {
+		t.Errorf("owned = %+v, want payments-api, ledger, unknown-tier", owned)
+	}
+	// A project the team both owns and is on call for is listed once, as owned
+	if len(result.Projects.Oncall) != 0 {
+		t.Errorf("on call = %+v, want none", result.Projects.Oncall)
+	}
+}
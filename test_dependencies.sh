@@ -5,8 +5,7 @@
 echo "Testing cerebro-mcp-server tools..."
 
 # Start the server in the background
-export HTTP_MODE=true
-./cerebro-mcp-server &
+./cerebro-mcp-server serve --transport http &
 SERVER_PID=$!
 
 # Wait for server to start
@@ -0,0 +1,35 @@
+package main
+
+import "strings"
+
+// testDeletedAt is when the soft-deleted projects and dependencies of fixtures were deleted
+const testDeletedAt = "2025-01-29T10:00:00Z"
+
+// deletedAt returns a new pointer to testDeletedAt for the DeletedAt field of fixtures
+func deletedAt() *string {
+	deleted := testDeletedAt
+	return &deleted
+}
+
+// testProject builds a minimal project for catalog fixtures; its name is the permalink
+// with a capital first letter
+func testProject(id int, permalink, owner, tier string) Project {
+	return Project{
+		ID:                        id,
+		Name:                      strings.ToUpper(permalink[:1]) + permalink[1:],
+		Permalink:                 permalink,
+		ProjectStakeholderOwner:   owner,
+		CriticalityTier:           CriticalityTier(tier),
+		Category:                  "Service",
+		CalculatedCriticalityTier: "Unknown",
+	}
+}
+
+// testDependency builds a required dependency edge for catalog fixtures
+func testDependency(id, dependent, provider int) ProjectDependency {
+	return ProjectDependency{
+		ID:                 id,
+		DependentProjectID: dependent,
+		ProvidingProjectID: provider,
+	}
+}
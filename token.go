@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenSource supplies the Cerebro API token
+type TokenSource interface {
+	// Token returns the current token
+	Token(ctx context.Context) (string, error)
+	// Invalidate discards any cached token so the next Token call reloads it
+	Invalidate()
+}
+
+// staticTokenSource always returns the same token
+type staticTokenSource struct {
+	token string
+}
+
+// StaticToken returns a TokenSource for a fixed token
+func StaticToken(token string) TokenSource {
+	return &staticTokenSource{token: token}
+}
+
+func (s *staticTokenSource) Token(ctx context.Context) (string, error) {
+	return s.token, nil
+}
+
+func (s *staticTokenSource) Invalidate() {}
+
+// fileTokenSource reads the token from a file and rereads it whenever the file changes,
+// which picks up rotated Kubernetes secrets without a restart
+type fileTokenSource struct {
+	path    string
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+// NewFileTokenSource returns a TokenSource backed by the file at path
+func NewFileTokenSource(path string) TokenSource {
+	return &fileTokenSource{path: path}
+}
+
+func (s *fileTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	if s.token != "" && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return s.token, nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", s.path)
+	}
+
+	s.token = token
+	s.modTime = info.ModTime()
+	s.size = info.Size()
+	return s.token, nil
+}
+
+func (s *fileTokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+}
+
+// commandTokenSource runs an external credential helper and caches its output
+// until the token is invalidated
+type commandTokenSource struct {
+	command string
+	mu      sync.Mutex
+	token   string
+}
+
+// NewCommandTokenSource returns a TokenSource that runs command with the shell and
+// uses its trimmed standard output as the token
+func NewCommandTokenSource(command string) TokenSource {
+	return &commandTokenSource{command: command}
+}
+
+func (s *commandTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" {
+		return s.token, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.command)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("token command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", fmt.Errorf("token command produced no output")
+	}
+
+	s.token = token
+	return s.token, nil
+}
+
+func (s *commandTokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileTokenSourceReloadsChangedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("first-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source := NewFileTokenSource(path)
+
+	token, err := source.Token(context.Background())
+	if err != nil || token != "first-token" {
+		t.Fatalf("Token = %q, %v; want first-token", token, err)
+	}
+
+	// A rotated secret has a new modification time; no Invalidate call is needed
+	if err := os.WriteFile(path, []byte("second-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if token, err := source.Token(context.Background()); err != nil || token != "second-token" {
+		t.Errorf("Token after rotation = %q, %v; want second-token", token, err)
+	}
+
+	if err := os.WriteFile(path, []byte("  \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.Token(context.Background()); err == nil || !strings.Contains(err.Error(), "is empty") {
+		t.Errorf("expected an error for an empty token file, got %v", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.Token(context.Background()); err == nil {
+		t.Error("expected an error for a missing token file")
+	}
+}
+
+func TestCommandTokenSource(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "calls")
+
+	tests := []struct {
+		name    string
+		command string
+		want    string
+		wantErr string
+	}{
+		{name: "success", command: "echo call >> " + counter + "; echo '  command-token  '", want: "command-token"},
+		{name: "failure", command: "echo 'helper locked' >&2; exit 3", wantErr: "helper locked"},
+		{name: "empty output", command: "printf '\\n'", wantErr: "produced no output"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := NewCommandTokenSource(tt.command)
+			token, err := source.Token(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Token = %q, %v; want an error containing %q", token, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || token != tt.want {
+				t.Fatalf("Token = %q, %v; want %q", token, err, tt.want)
+			}
+
+			// The token is cached until it is invalidated
+			source.Token(context.Background())
+			source.Invalidate()
+			source.Token(context.Background())
+			data, err := os.ReadFile(counter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if calls := strings.Count(string(data), "call"); calls != 2 {
+				t.Errorf("command ran %d times, want 2", calls)
+			}
+		})
+	}
+}
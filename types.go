@@ -6,36 +6,45 @@ type CerebroAPIParameters struct {
 	searchValue string
 	inlines     string
 	includes    string
+	page        int
+	perPage     int
 }
 
 // Project represents a project in the API response
 type Project struct {
-	ID                              int      `json:"id"`
-	Name                            string   `json:"name"`
-	Permalink                       string   `json:"permalink"`
-	Description                     string   `json:"description"`
-	StartedOn                       string   `json:"started_on"`
-	CreatedAt                       string   `json:"created_at"`
-	UpdatedAt                       string   `json:"updated_at"`
-	SlackChannel                    string   `json:"slack_channel"`
-	Nickname                        string   `json:"nickname"`
-	CPUUsage                        string   `json:"cpu_usage"`
-	MemoryUsage                     string   `json:"memory_usage"`
-	Category                        string   `json:"category"`
-	DeployTarget                    string   `json:"deploy_target"`
-	InScopeForSOC2                  string   `json:"in_scope_for_soc2"`
-	RunsOn                          string   `json:"runs_on"`
-	TFA                             string   `json:"tfa"`
-	CriticalityTier                 string   `json:"criticality_tier"`
-	CalculatedCriticalityTier       string   `json:"calculated_criticality_tier"`
-	ReleaseState                    string   `json:"release_state"`
-	LinkRepositoryURLs              []string `json:"link_repository_urls"`
-	ProjectRepositoryURLs           []string `json:"project_repository_urls"`
-	ProjectStakeholderOwner         string   `json:"project_stakeholder_owner_name"`
-	ProjectStakeholderOncall        string   `json:"project_stakeholder_oncall_name"`
-	DependentProjectDependenciesIds []int    `json:"dependent_project_dependencies_ids"`
-	PrimaryDeploymentUrl            string   `json:"link_deployment_url"`
-	AdditionalDeploymentUrls        []string `json:"link_deployment_urls"`
+	ID                              int             `json:"id"`
+	Name                            string          `json:"name"`
+	Permalink                       string          `json:"permalink"`
+	Description                     string          `json:"description"`
+	StartedOn                       string          `json:"started_on"`
+	CreatedAt                       string          `json:"created_at"`
+	UpdatedAt                       string          `json:"updated_at"`
+	SlackChannel                    string          `json:"slack_channel"`
+	SlackChannelAlerts              string          `json:"slack_channel_alerts"`
+	SlackChannelAlertsStaging       string          `json:"slack_channel_alerts_staging"`
+	SlackChannelDev                 string          `json:"slack_channel_dev"`
+	SlackChannelAlertsDev           string          `json:"slack_channel_alerts_dev"`
+	Nickname                        string          `json:"nickname"`
+	CPUUsage                        string          `json:"cpu_usage"`
+	MemoryUsage                     string          `json:"memory_usage"`
+	Category                        string          `json:"category"`
+	DeployTarget                    string          `json:"deploy_target"`
+	InScopeForSOC2                  YesNo           `json:"in_scope_for_soc2"`
+	RequiredForCompliance           bool            `json:"required_for_compliance"`
+	RunsOn                          RunsOn          `json:"runs_on"`
+	TFA                             YesNo           `json:"tfa"`
+	CriticalityTier                 CriticalityTier `json:"criticality_tier"`
+	CalculatedCriticalityTier       CriticalityTier `json:"calculated_criticality_tier"`
+	ReleaseState                    ReleaseState    `json:"release_state"`
+	LinkRepositoryURLs              []string        `json:"link_repository_urls"`
+	ProjectRepositoryURLs           []string        `json:"project_repository_urls"`
+	ProjectStakeholderOwner         string          `json:"project_stakeholder_owner_name"`
+	ProjectStakeholderOncall        string          `json:"project_stakeholder_oncall_name"`
+	DependentProjectDependenciesIds []int           `json:"dependent_project_dependencies_ids"`
+	PrimaryDeploymentUrl            string          `json:"link_deployment_url"`
+	AdditionalDeploymentUrls        []string        `json:"link_deployment_urls"`
+	RepositoriesIDs                 []int           `json:"repositories_ids"`
+	DeletedAt                       *string         `json:"deleted_at"`
 }
 
 // ProjectDependency represents a project dependency in the API response
@@ -50,11 +59,31 @@ type ProjectDependency struct {
 	DeletedAt          *string `json:"deleted_at"`
 }
 
+// Repository represents a source repository in the API response
+type Repository struct {
+	ID              int     `json:"id"`
+	Name            string  `json:"name"`
+	URL             string  `json:"url"`
+	Permalink       string  `json:"permalink"`
+	Category        string  `json:"category"`
+	KubeProject     string  `json:"kube_project"`
+	CreatedAt       string  `json:"created_at"`
+	UpdatedAt       string  `json:"updated_at"`
+	SyncedAt        *string `json:"synced_at"`
+	DeprecatedOn    *string `json:"deprecated_on"`
+	DeletedAt       *string `json:"deleted_at"`
+	Archived        bool    `json:"archived"`
+	Fork            bool    `json:"fork"`
+	OpenSource      bool    `json:"open_source"`
+	GithubSyncError bool    `json:"github_sync_error"`
+}
+
 // APIResponse represents the complete API response
 type APIResponse struct {
 	Pagination          map[string]interface{} `json:"pagination"`
 	Projects            []Project              `json:"projects"`
 	ProjectDependencies []ProjectDependency    `json:"project_dependencies"`
+	Repositories        []Repository           `json:"repositories"`
 }
 
 // HTTPRequest represents a simplified HTTP request for tool calls
@@ -80,5 +109,77 @@ type ProjectDetailsResult struct {
 type ProjectDependenciesResult struct {
 	Project             Project
 	ProjectDependencies []ProjectDependency
-	FormattedText       string
+	Dependencies        []DependencyDetail
+	// NextCursor continues the listing when it was cut short by a limit or the response budget
+	NextCursor    string
+	FormattedText string
+}
+
+// DependencyGraphResult represents the result of a dependency graph query
+type DependencyGraphResult struct {
+	Project       Project
+	Graph         *DependencyGraph
+	FormattedText string
+}
+
+// DependencyCyclesResult represents the result of catalog-wide cycle detection
+type DependencyCyclesResult struct {
+	Cycles        *DependencyCycles
+	FormattedText string
+}
+
+// CriticalityViolationsResult represents the result of a criticality tier consistency check
+type CriticalityViolationsResult struct {
+	Violations    *CriticalityViolations
+	FormattedText string
+}
+
+// DependencyPathResult represents the result of a dependency path search
+type DependencyPathResult struct {
+	Paths         *DependencyPaths
+	FormattedText string
+}
+
+// TeamProjectsResult represents the result of a team ownership query
+type TeamProjectsResult struct {
+	Projects      *TeamProjects
+	FormattedText string
+}
+
+// IncidentContactsResult represents the result of an incident contact sheet query
+type IncidentContactsResult struct {
+	Project       Project
+	Contacts      []IncidentContact
+	FormattedText string
+}
+
+// CatalogAuditResult represents the result of a catalog data-quality audit
+type CatalogAuditResult struct {
+	Audit         *CatalogAudit
+	FormattedText string
+}
+
+// ResourceUsageResult represents the result of a resource usage report
+type ResourceUsageResult struct {
+	Report        *ResourceUsageReport
+	FormattedText string
+}
+
+// ComplianceScopeResult represents the result of a compliance scope report
+type ComplianceScopeResult struct {
+	Scope         *ComplianceScope
+	FormattedText string
+}
+
+// CatalogDiffResult represents the result of a catalog diff
+type CatalogDiffResult struct {
+	Diff          *CatalogDiff
+	FormattedText string
+}
+
+// DependencyDetail pairs a dependency with its providing project, if it could be fetched
+type DependencyDetail struct {
+	Dependency       ProjectDependency `json:"dependency"`
+	ProvidingProject *Project          `json:"providing_project"`
+	Error            string            `json:"error,omitempty"`
 }
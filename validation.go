@@ -1,6 +1,10 @@
 package main
 
-import "strings"
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
 
 // Validator handles input validation
 type Validator struct{}
@@ -33,3 +37,119 @@ func (v *Validator) ValidateToolArguments(arguments map[string]interface{}) (str
 
 	return projectPermalink, v.ValidateProjectPermalink(projectPermalink)
 }
+
+// OptionalStringArgument extracts an optional string argument, returning "" when it is absent
+func (v *Validator) OptionalStringArgument(arguments map[string]interface{}, name string) (string, error) {
+	value, ok := arguments[name]
+	if !ok || value == nil {
+		return "", nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", &ValidationError{
+			Field:   name,
+			Message: "must be a string",
+		}
+	}
+	return strings.TrimSpace(s), nil
+}
+
+// OptionalStringListArgument extracts an optional list of strings, returning nil when it
+// is absent
+func (v *Validator) OptionalStringListArgument(arguments map[string]interface{}, name string) ([]string, error) {
+	value, ok := arguments[name]
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	var items []interface{}
+	switch list := value.(type) {
+	case []interface{}:
+		items = list
+	case []string:
+		return list, nil
+	default:
+		return nil, &ValidationError{Field: name, Message: "must be a list of strings"}
+	}
+
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, &ValidationError{Field: name, Message: "must be a list of strings"}
+		}
+		values = append(values, strings.TrimSpace(s))
+	}
+	return values, nil
+}
+
+// ValidateEnumList checks that every value of a list argument is one of allowed
+func (v *Validator) ValidateEnumList(name string, values []string, allowed ...string) error {
+	for _, value := range values {
+		if !slices.Contains(allowed, value) {
+			return &ValidationError{Field: name, Message: fmt.Sprintf("contains %q; values must be one of %s", value, strings.Join(allowed, ", "))}
+		}
+	}
+	return nil
+}
+
+// OptionalIntArgument extracts an optional whole-number argument within [min, max],
+// returning defaultValue when it is absent
+func (v *Validator) OptionalIntArgument(arguments map[string]interface{}, name string, defaultValue, min, max int) (int, error) {
+	value, ok := arguments[name]
+	if !ok || value == nil {
+		return defaultValue, nil
+	}
+
+	var n int
+	switch number := value.(type) {
+	case float64:
+		if number != float64(int(number)) {
+			return 0, &ValidationError{Field: name, Message: "must be a whole number"}
+		}
+		n = int(number)
+	case int:
+		n = number
+	default:
+		return 0, &ValidationError{Field: name, Message: "must be a number"}
+	}
+
+	if n < min || n > max {
+		return 0, &ValidationError{Field: name, Message: fmt.Sprintf("must be between %d and %d", min, max)}
+	}
+	return n, nil
+}
+
+// OptionalEnumArgument extracts an optional string argument that must be one of allowed,
+// returning the first allowed value when it is absent
+func (v *Validator) OptionalEnumArgument(arguments map[string]interface{}, name string, allowed ...string) (string, error) {
+	value, err := v.OptionalStringArgument(arguments, name)
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		return allowed[0], nil
+	}
+	for _, a := range allowed {
+		if value == a {
+			return value, nil
+		}
+	}
+	return "", &ValidationError{Field: name, Message: "must be one of " + strings.Join(allowed, ", ")}
+}
+
+// OptionalBoolArgument extracts an optional boolean argument, returning false when it is absent
+func (v *Validator) OptionalBoolArgument(arguments map[string]interface{}, name string) (bool, error) {
+	value, ok := arguments[name]
+	if !ok || value == nil {
+		return false, nil
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, &ValidationError{
+			Field:   name,
+			Message: "must be a boolean",
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// noOwnerTeam groups violations of projects without an owner
+const noOwnerTeam = "(no owner)"
+
+// CriticalityViolation is a required dependency on a provider with a less critical tier
+type CriticalityViolation struct {
+	Dependency ProjectDependency
+	Dependent  Project
+	Provider   Project
+}
+
+// TeamViolations are the violations of the projects owned by one team
+type TeamViolations struct {
+	Team       string
+	Violations []CriticalityViolation
+}
+
+// CriticalityViolations is the result of a criticality tier consistency check
+type CriticalityViolations struct {
+	// Checked is the number of required dependencies between projects with known tiers
+	Checked int
+	// Unranked is the number of required dependencies skipped because a tier is unknown
+	Unranked int
+	Teams    []TeamViolations
+}
+
+// GetCriticalityViolations loads the whole catalog and reports required dependencies on
+// less critical providers, grouped by the owner of the dependent project. A non-empty team
+// limits the report to that owner.
+func (s *ProjectService) GetCriticalityViolations(ctx context.Context, team string) (*CriticalityViolationsResult, error) {
+	catalog, err := s.source.Catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := findCriticalityViolations(newCatalogGraph(catalog), team)
+	return &CriticalityViolationsResult{
+		Violations:    violations,
+		FormattedText: formatCriticalityViolations(violations, team),
+	}, nil
+}
+
+// findCriticalityViolations checks every required dependency of the catalog
+func findCriticalityViolations(graph *catalogGraph, team string) *CriticalityViolations {
+	result := &CriticalityViolations{}
+	byTeam := make(map[string][]CriticalityViolation)
+
+	for _, id := range graph.ids {
+		dependent := graph.projects[id]
+		owner := dependent.ProjectStakeholderOwner
+		if owner == "" {
+			owner = noOwnerTeam
+		}
+		if team != "" && !strings.EqualFold(owner, team) {
+			continue
+		}
+
+		for _, dep := range graph.edges[id] {
+			if dep.Optional {
+				continue
+			}
+			provider := graph.projects[dep.ProvidingProjectID]
+
+			dependentRank, dependentRanked := effectiveCriticalityTier(dependent).Rank()
+			providerRank, providerRanked := effectiveCriticalityTier(provider).Rank()
+			if !dependentRanked || !providerRanked {
+				result.Unranked++
+				continue
+			}
+
+			result.Checked++
+			if providerRank > dependentRank {
+				byTeam[owner] = append(byTeam[owner], CriticalityViolation{Dependency: dep, Dependent: dependent, Provider: provider})
+			}
+		}
+	}
+
+	for owner, violations := range byTeam {
+		sort.SliceStable(violations, func(i, j int) bool {
+			ri, _ := effectiveCriticalityTier(violations[i].Dependent).Rank()
+			rj, _ := effectiveCriticalityTier(violations[j].Dependent).Rank()
+			if ri != rj {
+				return ri < rj
+			}
+			return violations[i].Dependent.Permalink < violations[j].Dependent.Permalink
+		})
+		result.Teams = append(result.Teams, TeamViolations{Team: owner, Violations: violations})
+	}
+	sort.Slice(result.Teams, func(i, j int) bool {
+		if len(result.Teams[i].Violations) != len(result.Teams[j].Violations) {
+			return len(result.Teams[i].Violations) > len(result.Teams[j].Violations)
+		}
+		return result.Teams[i].Team < result.Teams[j].Team
+	})
+
+	return result
+}
+
+// count returns the total number of violations
+func (v *CriticalityViolations) count() int {
+	total := 0
+	for _, team := range v.Teams {
+		total += len(team.Violations)
+	}
+	return total
+}
+
+// formatCriticalityViolations formats criticality violations grouped by team for display
+func formatCriticalityViolations(violations *CriticalityViolations, team string) string {
+	result := "# Criticality Tier Violations\n\n"
+	if team != "" {
+		result = fmt.Sprintf("# Criticality Tier Violations for: %s\n\n", team)
+	}
+
+	result += fmt.Sprintf("Checked %d required dependencies; found %d where a project depends on a less critical provider.\n", violations.Checked, violations.count())
+	if violations.Unranked > 0 {
+		result += fmt.Sprintf("Skipped %d required dependencies where a criticality tier is unknown.\n", violations.Unranked)
+	}
+
+	if len(violations.Teams) == 0 {
+		return result + "\nNo violations found.\n"
+	}
+
+	for _, team := range violations.Teams {
+		result += fmt.Sprintf("\n## %s (%d)\n", team.Team, len(team.Violations))
+		for _, v := range team.Violations {
+			result += fmt.Sprintf("- `%s` (%s) → `%s` (%s, owner: %s)",
+				v.Dependent.Permalink, effectiveCriticalityTier(v.Dependent),
+				v.Provider.Permalink, effectiveCriticalityTier(v.Provider), valueOrNone(v.Provider.ProjectStakeholderOwner))
+			if v.Dependency.Description != "" {
+				result += fmt.Sprintf(": %s", v.Dependency.Description)
+			}
+			result += "\n"
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+// violationSnapshot returns a catalog where Team Payments and Team Checkout own projects
+// that require less critical providers
+func violationSnapshot() *Snapshot {
+	dependency := func(id, dependent, provider int, optional bool) ProjectDependency {
+		dep := testDependency(id, dependent, provider)
+		dep.Optional = optional
+		dep.Description = "Needed for payments"
+		return dep
+	}
+	// The calculated tier wins over the declared tier
+	recalculated := testProject(5, "fraud-check", "Team Risk", "Tier 1")
+	recalculated.CalculatedCriticalityTier = "Tier 3"
+
+	return &Snapshot{
+		Projects: []Project{
+			testProject(1, "payments-api", "Team Payments", "Tier 0"),
+			testProject(2, "ledger", "Team Payments", "Tier 1"),
+			testProject(3, "reporting", "Team Data", "Tier 2"),
+			testProject(4, "checkout", "Team Checkout", "Tier 1"),
+			recalculated,
+			testProject(6, "experiments", "Team Growth", ""),
+		},
+		ProjectDependencies: []ProjectDependency{
+			dependency(1, 1, 2, false),
+			dependency(2, 1, 3, false),
+			dependency(3, 2, 3, false),
+			dependency(4, 4, 3, true),
+			dependency(5, 4, 5, false),
+			dependency(6, 4, 6, false),
+			dependency(7, 3, 1, false),
+		},
+	}
+}
+
+func TestGetCriticalityViolations(t *testing.T) {
+	service := NewProjectService(NewSnapshotSource(violationSnapshot()), NewValidator())
+
+	result, err := service.GetCriticalityViolations(context.Background(), "")
+	if err != nil {
+		t.Fatalf("GetCriticalityViolations failed: %v", err)
+	}
+
+	violations := result.Violations
+	if violations.Checked != 5 || violations.Unranked != 1 {
+		t.Errorf("checked %d and skipped %d, want 5 and 1", violations.Checked, violations.Unranked)
+	}
+	if len(violations.Teams) != 2 || violations.Teams[0].Team != "Team Payments" || len(violations.Teams[0].Violations) != 3 {
+		t.Fatalf("unexpected grouping: %+v", violations.Teams)
+	}
+	if got := violations.Teams[1].Violations[0].Provider.Permalink; got != "fraud-check" {
+		t.Errorf("expected the calculated tier of fraud-check to count, got %s", got)
+	}
+	assertGolden(t, "criticality_violations", result.FormattedText)
+
+	filtered, err := service.GetCriticalityViolations(context.Background(), "team checkout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered.Violations.Teams) != 1 || filtered.Violations.Teams[0].Team != "Team Checkout" {
+		t.Errorf("team filter returned %+v", filtered.Violations.Teams)
+	}
+}
+
+func TestCriticalityViolationsUncheckedProvidersAndTierOrder(t *testing.T) {
+	retired := testProject(3, "old-reporting", "Team Data", "Tier 3")
+	retired.DeletedAt = deletedAt()
+	removed := testDependency(3, 1, 4)
+	removed.DeletedAt = deletedAt()
+	lowercase := testProject(5, "archive", "Team Data", " tier 10 ")
+
+	snapshot := &Snapshot{
+		Projects: []Project{
+			testProject(1, "payments-api", "", "Tier 0"),
+			testProject(2, "ledger", "Team Payments", "Tier 2"),
+			retired,
+			testProject(4, "reporting", "Team Data", "Tier 3"),
+			lowercase,
+		},
+		ProjectDependencies: []ProjectDependency{
+			testDependency(1, 2, 3),
+			testDependency(2, 2, 99),
+			removed,
+			testDependency(4, 2, 5),
+			testDependency(5, 1, 2),
+		},
+	}
+	service := NewProjectService(NewSnapshotSource(snapshot), NewValidator())
+
+	result, err := service.GetCriticalityViolations(context.Background(), "")
+	if err != nil {
+		t.Fatalf("GetCriticalityViolations failed: %v", err)
+	}
+	violations := result.Violations
+
+	// Deleted, missing and soft-deleted providers are not checked
+	if violations.Checked != 2 || violations.Unranked != 0 {
+		t.Errorf("checked %d and skipped %d, want 2 and 0", violations.Checked, violations.Unranked)
+	}
+	if len(violations.Teams) != 2 {
+		t.Fatalf("unexpected grouping: %+v", violations.Teams)
+	}
+	if got := violations.Teams[0]; got.Team != noOwnerTeam || got.Violations[0].Provider.Permalink != "ledger" {
+		t.Errorf("expected the ownerless payments-api under %q, got %+v", noOwnerTeam, got)
+	}
+	// Tier 10 is less critical than Tier 2 even though it sorts first as a string
+	if got := violations.Teams[1]; got.Team != "Team Payments" || got.Violations[0].Provider.Permalink != "archive" {
+		t.Errorf("expected ledger → archive for Team Payments, got %+v", got)
+	}
+
+	none, err := service.GetCriticalityViolations(context.Background(), "Team Nobody")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(none.Violations.Teams) != 0 {
+		t.Errorf("unknown team returned %+v", none.Violations.Teams)
+	}
+}